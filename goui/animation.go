@@ -0,0 +1,484 @@
+package goui
+
+import (
+	"image/color"
+	"math"
+	"sort"
+	"time"
+)
+
+// Easing maps the progress of an animation, from 0 to 1, to how far the values have changed
+type Easing func(t float64) float64
+
+// The easing curves of css
+var (
+	Linear    Easing = func(t float64) float64 { return t }
+	Ease             = CubicBezier(0.25, 0.1, 0.25, 1)
+	EaseIn           = CubicBezier(0.42, 0, 1, 1)
+	EaseOut          = CubicBezier(0, 0, 0.58, 1)
+	EaseInOut        = CubicBezier(0.42, 0, 0.58, 1)
+)
+
+// CubicBezier returns an easing curve like the css cubic-bezier function, which starts at 0, 0 and ends at 1, 1
+func CubicBezier(x1, y1, x2, y2 float64) Easing {
+	bezier := func(a, b, t float64) float64 {
+		u := 1 - t
+		return 3*u*u*t*a + 3*u*t*t*b + t*t*t
+	}
+	return func(x float64) float64 {
+		if x <= 0 || x >= 1 {
+			return x
+		}
+		// find the t for x by bisection, x grows with t since x1 and x2 are between 0 and 1
+		low, high := 0.0, 1.0
+		t := x
+		for i := 0; i < 30; i++ {
+			if bezier(x1, x2, t) < x {
+				low = t
+			} else {
+				high = t
+			}
+			t = (low + high) / 2
+		}
+		return bezier(y1, y2, t)
+	}
+}
+
+// Property is a style that can be animated with a transition
+type Property int
+
+const (
+	PropertyAll Property = iota
+	PropertyBackground
+	PropertyColor
+	PropertyBorderColor
+	PropertyBorderWidth
+	PropertyBorderRadius
+	PropertyOpacity
+	PropertyTranslate
+	PropertyScale
+	PropertyRotate
+	PropertyWidth
+	PropertyHeight
+	PropertyFontSize
+	propertyCount
+)
+
+func (value Property) String() string {
+	switch value {
+	case PropertyAll:
+		return "all"
+	case PropertyBackground:
+		return "background"
+	case PropertyColor:
+		return "color"
+	case PropertyBorderColor:
+		return "border-color"
+	case PropertyBorderWidth:
+		return "border-width"
+	case PropertyBorderRadius:
+		return "border-radius"
+	case PropertyOpacity:
+		return "opacity"
+	case PropertyTranslate:
+		return "translate"
+	case PropertyScale:
+		return "scale"
+	case PropertyRotate:
+		return "rotate"
+	case PropertyWidth:
+		return "width"
+	case PropertyHeight:
+		return "height"
+	case PropertyFontSize:
+		return "font-size"
+	}
+	return "unknown"
+}
+
+// animatedProperty reads and writes a property as numbers, so that it can be interpolated
+type animatedProperty struct {
+	// value returns the value of the property, or false if it is not set
+	value func(s Styles) ([]float64, bool)
+	// initial returns the value used when it is not set, or false if there is none (like for the auto width)
+	initial func(s Styles) ([]float64, bool)
+	set     func(s Styles, v []float64) Styles
+}
+
+var animatedProperties = [propertyCount]animatedProperty{
+	PropertyBackground: {
+		value:   func(s Styles) ([]float64, bool) { return colorValue(s.background) },
+		initial: func(s Styles) ([]float64, bool) { return colorValue(color.RGBA{}) },
+		set:     func(s Styles, v []float64) Styles { s.background = valueColor(v); return s },
+	},
+	PropertyColor: {
+		value:   func(s Styles) ([]float64, bool) { return colorValue(s.color) },
+		initial: func(s Styles) ([]float64, bool) { return colorValue(s.theme.color("text.primary")) },
+		set:     func(s Styles, v []float64) Styles { s.color = valueColor(v); return s },
+	},
+	PropertyBorderColor: {
+		value: func(s Styles) ([]float64, bool) {
+			c := s.borderColor
+			if c.top == nil && c.right == nil && c.bottom == nil && c.left == nil {
+				return nil, false
+			}
+			return borderColorValue(s), true
+		},
+		initial: func(s Styles) ([]float64, bool) { return borderColorValue(s), true },
+		set: func(s Styles, v []float64) Styles {
+			s.borderColor = edgeColors{valueColor(v[0:4]), valueColor(v[4:8]), valueColor(v[8:12]), valueColor(v[12:16])}
+			return s
+		},
+	},
+	PropertyBorderWidth: {
+		value: func(s Styles) ([]float64, bool) {
+			e := s.borderWidth
+			return []float64{e.top, e.right, e.bottom, e.left}, e.top != unset || e.right != unset || e.bottom != unset || e.left != unset
+		},
+		initial: func(s Styles) ([]float64, bool) {
+			e := s.borderWidth
+			return []float64{zeroIfUnset(e.top), zeroIfUnset(e.right), zeroIfUnset(e.bottom), zeroIfUnset(e.left)}, true
+		},
+		set: func(s Styles, v []float64) Styles {
+			s.borderWidth = edges{v[0], v[1], v[2], v[3]}
+			return s
+		},
+	},
+	PropertyBorderRadius: {
+		value: func(s Styles) ([]float64, bool) {
+			c := s.borderRadius
+			set := c.topLeft != unset || c.topRight != unset || c.bottomRight != unset || c.bottomLeft != unset
+			return []float64{c.topLeft, c.topRight, c.bottomRight, c.bottomLeft}, set
+		},
+		initial: func(s Styles) ([]float64, bool) {
+			c := s.borderRadius
+			return []float64{zeroIfUnset(c.topLeft), zeroIfUnset(c.topRight), zeroIfUnset(c.bottomRight), zeroIfUnset(c.bottomLeft)}, true
+		},
+		set: func(s Styles, v []float64) Styles {
+			s.borderRadius = corners{v[0], v[1], v[2], v[3]}
+			return s
+		},
+	},
+	PropertyOpacity: {
+		value:   func(s Styles) ([]float64, bool) { return []float64{s.opacity}, s.opacity != unset },
+		initial: func(s Styles) ([]float64, bool) { return []float64{1}, true },
+		set:     func(s Styles, v []float64) Styles { return s.Opacity(math.Max(0, math.Min(1, v[0]))) },
+	},
+	PropertyTranslate: {
+		value: func(s Styles) ([]float64, bool) {
+			return []float64{s.transform.translateX, s.transform.translateY}, s.transform.set&transformTranslate != 0
+		},
+		initial: func(s Styles) ([]float64, bool) { return []float64{0, 0}, true },
+		set:     func(s Styles, v []float64) Styles { return s.Translate(v[0], v[1]) },
+	},
+	PropertyScale: {
+		value: func(s Styles) ([]float64, bool) {
+			return []float64{s.transform.scaleX, s.transform.scaleY}, s.transform.set&transformScale != 0
+		},
+		initial: func(s Styles) ([]float64, bool) { return []float64{1, 1}, true },
+		set:     func(s Styles, v []float64) Styles { return s.Scale(v[0], v[1]) },
+	},
+	PropertyRotate: {
+		value: func(s Styles) ([]float64, bool) {
+			return []float64{s.transform.rotate}, s.transform.set&transformRotate != 0
+		},
+		initial: func(s Styles) ([]float64, bool) { return []float64{0}, true },
+		set:     func(s Styles, v []float64) Styles { return s.Rotate(v[0]) },
+	},
+	PropertyWidth: {
+		value:   func(s Styles) ([]float64, bool) { return []float64{s.width.value}, s.width.unit == unitPx },
+		initial: func(s Styles) ([]float64, bool) { return nil, false },
+		set:     func(s Styles, v []float64) Styles { return s.Width(v[0]) },
+	},
+	PropertyHeight: {
+		value:   func(s Styles) ([]float64, bool) { return []float64{s.height.value}, s.height.unit == unitPx },
+		initial: func(s Styles) ([]float64, bool) { return nil, false },
+		set:     func(s Styles, v []float64) Styles { return s.Height(v[0]) },
+	},
+	PropertyFontSize: {
+		value:   func(s Styles) ([]float64, bool) { return []float64{s.fontSize}, s.fontSize != unset },
+		initial: func(s Styles) ([]float64, bool) { return []float64{defaultFontSize}, true },
+		set:     func(s Styles, v []float64) Styles { return s.FontSize(v[0]) },
+	},
+}
+
+// current returns the value of the property in the styles, which is the initial value when it is not set
+func (p animatedProperty) current(s Styles) ([]float64, bool) {
+	if v, ok := p.value(s); ok {
+		return v, true
+	}
+	return p.initial(s)
+}
+
+func colorValue(c color.Color) ([]float64, bool) {
+	if c == nil {
+		return nil, false
+	}
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return []float64{float64(n.R), float64(n.G), float64(n.B), float64(n.A)}, true
+}
+
+func valueColor(v []float64) color.Color {
+	channel := func(c float64) uint8 {
+		return uint8(math.Max(0, math.Min(255, math.Round(c))))
+	}
+	return color.NRGBA{R: channel(v[0]), G: channel(v[1]), B: channel(v[2]), A: channel(v[3])}
+}
+
+// borderColorValue returns the colors of the four sides, which are the text color when they are not set
+func borderColorValue(s Styles) []float64 {
+	var v []float64
+	for _, side := range borderSides(s) {
+		c, _ := colorValue(side.color)
+		v = append(v, c...)
+	}
+	return v
+}
+
+func zeroIfUnset(v float64) float64 {
+	if v == unset {
+		return 0
+	}
+	return v
+}
+
+func interpolate(from, to []float64, t float64) []float64 {
+	v := make([]float64, len(to))
+	for i := range to {
+		v[i] = from[i] + (to[i]-from[i])*t
+	}
+	return v
+}
+
+func equalValues(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+type transition struct {
+	property Property
+	duration time.Duration
+	easing   Easing
+}
+
+// runningTransition is the state of a transition of one property of a widget, kept between renders
+type runningTransition struct {
+	from, to, current []float64
+	start             time.Time
+	duration          time.Duration
+	easing            Easing
+}
+
+// transition animates the properties of a keyed widget that have a transition from the value
+// they had in the last render to the value of the styles
+func (g *gui) transition(w *widgetContainer, s Styles) Styles {
+	if w.handle.key == "" || len(s.transitions) == 0 {
+		return s
+	}
+
+	// later transitions of the same property replace earlier ones, like in css
+	var byProperty [propertyCount]*transition
+	for i := range s.transitions {
+		t := &s.transitions[i]
+		if t.property == PropertyAll {
+			for p := range byProperty {
+				byProperty[p] = t
+			}
+		} else {
+			byProperty[t.property] = t
+		}
+	}
+
+	running := g.widgetState(w, "transitions", func() interface{} {
+		return map[Property]*runningTransition{}
+	}).(map[Property]*runningTransition)
+
+	for p, t := range byProperty {
+		property := Property(p)
+		if t == nil || property == PropertyAll {
+			continue
+		}
+		prop := animatedProperties[property]
+		to, ok := prop.current(s)
+		if !ok {
+			delete(running, property)
+			continue
+		}
+
+		run, ok := running[property]
+		if !ok {
+			// nothing to animate from in the first render
+			running[property] = &runningTransition{from: to, to: to, current: to}
+			continue
+		}
+		if !equalValues(run.to, to) {
+			*run = runningTransition{from: run.current, to: to, start: g.frameTime, duration: t.duration, easing: t.easing}
+		}
+
+		progress := 1.0
+		if run.duration > 0 {
+			progress = math.Min(1, float64(g.frameTime.Sub(run.start))/float64(run.duration))
+		}
+		if progress < 1 {
+			g.animating = true
+			run.current = interpolate(run.from, run.to, ease(run.easing, progress))
+		} else {
+			run.current = run.to
+		}
+		s = prop.set(s, run.current)
+	}
+	return s
+}
+
+func ease(easing Easing, t float64) float64 {
+	if easing == nil {
+		return Ease(t)
+	}
+	return easing(t)
+}
+
+// Infinite repeats an animation for as long as it is rendered
+const Infinite = -1
+
+// Keyframe is the styles of an animation at an offset from 0 at the start to 1 at the end.
+// Only properties that can be transitioned are animated.
+type Keyframe struct {
+	Offset float64
+	Styles Styles
+}
+
+// Animation animates the styles of a widget through keyframes, like a css animation. Properties that are
+// missing in the first or last keyframe animate from or to the styles of the widget.
+type Animation struct {
+	Keyframes []Keyframe
+	Duration  time.Duration
+	Delay     time.Duration
+	// Easing is applied between every two keyframes, it is Ease by default
+	Easing Easing
+	// Iterations is how often the animation runs, Infinite runs it forever. 0 and other numbers
+	// below 1 run it once, like the default of css.
+	Iterations int
+	// Alternate runs every second iteration backwards
+	Alternate bool
+	// OnComplete is called on the UI thread after the last iteration, after which the widget
+	// has its own styles again
+	OnComplete func()
+}
+
+type namedAnimation struct {
+	name      string
+	animation Animation
+}
+
+// runningAnimation is the state of an animation of a widget, kept between renders
+type runningAnimation struct {
+	start    time.Time
+	finished bool
+}
+
+// animate applies the keyframe animations of the widget to its styles
+func (g *gui) animate(w *widgetContainer, s Styles) Styles {
+	for _, named := range w.handle.animations {
+		a := named.animation
+		run := g.widgetState(w, "animation:"+named.name, func() interface{} {
+			return &runningAnimation{start: g.frameTime}
+		}).(*runningAnimation)
+		if run.finished {
+			continue
+		}
+
+		elapsed := g.frameTime.Sub(run.start) - a.Delay
+		g.animating = true
+		if elapsed < 0 {
+			continue
+		}
+
+		iterations := a.Iterations
+		if iterations < 1 && iterations != Infinite {
+			iterations = 1
+		}
+		iteration, progress := 0, 1.0
+		if a.Duration > 0 {
+			iteration = int(elapsed / a.Duration)
+			progress = float64(elapsed%a.Duration) / float64(a.Duration)
+		} else {
+			iteration = iterations
+		}
+		if iterations != Infinite && iteration >= iterations {
+			run.finished = true
+			if a.OnComplete != nil {
+				g.Post(a.OnComplete)
+			}
+			continue
+		}
+
+		if a.Alternate && iteration%2 == 1 {
+			progress = 1 - progress
+		}
+		s = a.stylesAt(s, progress)
+	}
+	return s
+}
+
+type keyframeValue struct {
+	offset float64
+	value  []float64
+}
+
+// stylesAt returns the styles with the animated properties at the progress of the animation
+func (a Animation) stylesAt(s Styles, progress float64) Styles {
+	for p := PropertyAll + 1; p < propertyCount; p++ {
+		prop := animatedProperties[p]
+
+		var frames []keyframeValue
+		for _, k := range a.Keyframes {
+			if v, ok := prop.value(k.Styles.themed(s.theme)); ok {
+				frames = append(frames, keyframeValue{k.Offset, v})
+			}
+		}
+		if len(frames) == 0 {
+			continue
+		}
+		if own, ok := prop.current(s); ok {
+			if frames[0].offset > 0 {
+				frames = append([]keyframeValue{{0, own}}, frames...)
+			}
+			if frames[len(frames)-1].offset < 1 {
+				frames = append(frames, keyframeValue{1, own})
+			}
+		}
+
+		value := frames[0].value
+		for i := 1; i < len(frames); i++ {
+			from, to := frames[i-1], frames[i]
+			if progress < from.offset {
+				break
+			}
+			if progress >= to.offset || to.offset <= from.offset {
+				value = to.value
+				continue
+			}
+			t := (progress - from.offset) / (to.offset - from.offset)
+			value = interpolate(from.value, to.value, ease(a.Easing, t))
+			break
+		}
+		s = prop.set(s, value)
+	}
+	return s
+}
+
+// sortKeyframes returns the keyframes ordered by their offset
+func sortKeyframes(keyframes []Keyframe) []Keyframe {
+	sorted := append([]Keyframe{}, keyframes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Offset < sorted[j].Offset
+	})
+	return sorted
+}
@@ -0,0 +1,184 @@
+package goui
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEasing(t *testing.T) {
+	tests := []struct {
+		name   string
+		easing Easing
+		t      float64
+		want   float64
+	}{
+		{"linear", Linear, 0.25, 0.25},
+		{"ease start", Ease, 0, 0},
+		{"ease end", Ease, 1, 1},
+		{"ease", Ease, 0.5, 0.8024},
+		{"ease in", EaseIn, 0.5, 0.3153},
+		{"ease out", EaseOut, 0.5, 0.6847},
+		{"ease in out", EaseInOut, 0.5, 0.5},
+		{"straight bezier", CubicBezier(0, 0, 1, 1), 0.3, 0.3},
+	}
+	for _, test := range tests {
+		if got := test.easing(test.t); math.Abs(got-test.want) > 0.001 {
+			t.Errorf("%v at %v is %v, expected %v", test.name, test.t, got, test.want)
+		}
+	}
+}
+
+func opacityOf(t *testing.T, d *Driver, key string) float64 {
+	t.Helper()
+	return math.Round(find(t, d, key).Styles.opacity*1000) / 1000
+}
+
+func TestDriverTransition(t *testing.T) {
+	tests := []struct {
+		name   string
+		easing Easing
+		want   float64 // opacity half way
+	}{
+		{"linear", Linear, 0.5},
+		{"ease in", EaseIn, 1 - 0.315},
+		{"default", nil, 1 - 0.802},
+	}
+	for _, test := range tests {
+		hidden := false
+		d := newTestDriver(t, 100, 100, func(g UI) {
+			opacity := 1.0
+			if hidden {
+				opacity = 0
+			}
+			g.Box(func() {}).Key("box").
+				Styles(NewStyles().Width(10).Height(10).Opacity(opacity).Transition(PropertyOpacity, 100*time.Millisecond, test.easing))
+		})
+
+		hidden = true
+		d.g.Rerender()
+		d.Step()
+		if got := opacityOf(t, d, "box"); got != 1 {
+			t.Errorf("%v: opacity %v at the start of the transition", test.name, got)
+		}
+		d.Advance(50 * time.Millisecond)
+		if got := opacityOf(t, d, "box"); got != test.want {
+			t.Errorf("%v: opacity %v half way, expected %v", test.name, got, test.want)
+		}
+		d.Advance(50 * time.Millisecond)
+		if got := opacityOf(t, d, "box"); got != 0 {
+			t.Errorf("%v: opacity %v at the end", test.name, got)
+		}
+		if d.g.animating {
+			t.Errorf("%v: still animating after the transition", test.name)
+		}
+	}
+}
+
+func TestDriverTransitionReversed(t *testing.T) {
+	hidden := false
+	d := newTestDriver(t, 100, 100, func(g UI) {
+		opacity := 1.0
+		if hidden {
+			opacity = 0
+		}
+		g.Box(func() {}).Key("box").
+			Styles(NewStyles().Opacity(opacity).Transition(PropertyOpacity, 100*time.Millisecond, Linear))
+	})
+
+	hidden = true
+	d.g.Rerender()
+	d.Step()
+	d.Advance(40 * time.Millisecond)
+
+	// changing back starts from where the transition is
+	hidden = false
+	d.g.Rerender()
+	d.Step()
+	if got := opacityOf(t, d, "box"); got != 0.6 {
+		t.Errorf("opacity %v after changing back", got)
+	}
+	d.Advance(50 * time.Millisecond)
+	if got := opacityOf(t, d, "box"); got != 0.8 {
+		t.Errorf("opacity %v half way back", got)
+	}
+}
+
+func fade(iterations int, alternate bool, done *int) Animation {
+	return Animation{
+		Keyframes:  []Keyframe{{0, NewStyles().Opacity(0)}, {1, NewStyles().Opacity(1)}},
+		Duration:   100 * time.Millisecond,
+		Easing:     Linear,
+		Iterations: iterations,
+		Alternate:  alternate,
+		OnComplete: func() { *done++ },
+	}
+}
+
+func TestDriverAnimationAlternate(t *testing.T) {
+	done := 0
+	d := newTestDriver(t, 100, 100, func(g UI) {
+		g.Box(func() {}).Key("box").Styles(NewStyles().Opacity(0.5)).Animate("fade", fade(2, true, &done))
+	})
+
+	steps := []struct {
+		advance time.Duration
+		opacity float64
+	}{
+		{25 * time.Millisecond, 0.25},
+		{50 * time.Millisecond, 0.75},
+		// the second iteration runs backwards
+		{50 * time.Millisecond, 0.75},
+		{50 * time.Millisecond, 0.25},
+		// after the last iteration the widget has its own styles again
+		{50 * time.Millisecond, 0.5},
+	}
+	for i, step := range steps {
+		d.Advance(step.advance)
+		if got := opacityOf(t, d, "box"); got != step.opacity {
+			t.Errorf("step %v: opacity %v, expected %v", i, got, step.opacity)
+		}
+	}
+
+	d.Step()
+	if done != 1 {
+		t.Errorf("OnComplete was called %v times", done)
+	}
+}
+
+func TestDriverAnimationIterations(t *testing.T) {
+	tests := []struct {
+		iterations int
+		want       int // number of iterations that run, -1 for all of them
+	}{
+		{0, 1},
+		{1, 1},
+		{3, 3},
+		{-5, 1},
+		{Infinite, -1},
+	}
+	for _, test := range tests {
+		done := 0
+		d := newTestDriver(t, 100, 100, func(g UI) {
+			g.Box(func() {}).Key("box").Animate("fade", fade(test.iterations, false, &done))
+		})
+
+		ran := 0
+		for ; ran < 10; ran++ {
+			d.Advance(50 * time.Millisecond)
+			if got := opacityOf(t, d, "box"); got != 0.5 {
+				break
+			}
+			d.Advance(50 * time.Millisecond)
+		}
+		d.Step()
+
+		if test.want == -1 {
+			if ran != 10 || done != 0 {
+				t.Errorf("iterations %v: stopped after %v iterations", test.iterations, ran)
+			}
+		} else if ran != test.want || done != 1 {
+			t.Errorf("iterations %v: ran %v times and completed %v times, expected %v", test.iterations, ran, done, test.want)
+		}
+	}
+}
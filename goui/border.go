@@ -0,0 +1,232 @@
+package goui
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/kjk/flex"
+	"github.com/shibukawa/nanovgo"
+)
+
+// kappa is the distance of the bezier control points to draw a quarter circle, same as nanovgo
+const kappa = 0.5522847493
+
+// radii returns the corner radii of a box in the order top left, top right, bottom right, bottom left.
+// They are limited to half of the box so that the corners don't overlap.
+func radii(s Styles, w, h float32) [4]float32 {
+	limit := minF(w, h) / 2
+	r := [4]float32{
+		checkUnsetF(s.borderRadius.topLeft, 0),
+		checkUnsetF(s.borderRadius.topRight, 0),
+		checkUnsetF(s.borderRadius.bottomRight, 0),
+		checkUnsetF(s.borderRadius.bottomLeft, 0),
+	}
+	for i := range r {
+		r[i] = maxF(0, minF(r[i], limit))
+	}
+	return r
+}
+
+// insetRadii returns the radii of a box that is inset from a box with radii r
+func insetRadii(r [4]float32, inset float32) [4]float32 {
+	for i := range r {
+		r[i] = maxF(0, r[i]-inset)
+	}
+	return r
+}
+
+// paddingRadii returns the radii of the corners inside the border
+func paddingRadii(r [4]float32, l *flex.Node) [4]float32 {
+	top, right := l.LayoutGetBorder(flex.EdgeTop), l.LayoutGetBorder(flex.EdgeRight)
+	bottom, left := l.LayoutGetBorder(flex.EdgeBottom), l.LayoutGetBorder(flex.EdgeLeft)
+	return [4]float32{
+		maxF(0, r[0]-maxF(top, left)),
+		maxF(0, r[1]-maxF(top, right)),
+		maxF(0, r[2]-maxF(bottom, right)),
+		maxF(0, r[3]-maxF(bottom, left)),
+	}
+}
+
+// roundedRect adds a rectangle with a different radius for every corner to the path
+func roundedRect(ctx canvas, x, y, w, h float32, r [4]float32) {
+	tl, tr, br, bl := r[0], r[1], r[2], r[3]
+	if tl < 0.1 && tr < 0.1 && br < 0.1 && bl < 0.1 {
+		ctx.Rect(x, y, w, h)
+		return
+	}
+
+	ctx.MoveTo(x, y+tl)
+	ctx.LineTo(x, y+h-bl)
+	ctx.BezierTo(x, y+h-bl*(1-kappa), x+bl*(1-kappa), y+h, x+bl, y+h)
+	ctx.LineTo(x+w-br, y+h)
+	ctx.BezierTo(x+w-br*(1-kappa), y+h, x+w, y+h-br*(1-kappa), x+w, y+h-br)
+	ctx.LineTo(x+w, y+tr)
+	ctx.BezierTo(x+w, y+tr*(1-kappa), x+w-tr*(1-kappa), y, x+w-tr, y)
+	ctx.LineTo(x+tl, y)
+	ctx.BezierTo(x+tl*(1-kappa), y, x, y+tl*(1-kappa), x, y+tl)
+	ctx.ClosePath()
+}
+
+type borderSide struct {
+	width float32
+	color color.Color
+	style BorderStyle
+}
+
+// borderSides returns the borders in the order top, right, bottom, left.
+// Like in css the border has the color of the text if no color is given.
+func borderSides(s Styles) [4]borderSide {
+	textColor := s.color
+	if textColor == nil {
+		textColor = s.theme.color("text.primary")
+	}
+
+	sides := [4]borderSide{
+		{checkUnsetF(s.borderWidth.top, 0), s.borderColor.top, s.borderStyle.top},
+		{checkUnsetF(s.borderWidth.right, 0), s.borderColor.right, s.borderStyle.right},
+		{checkUnsetF(s.borderWidth.bottom, 0), s.borderColor.bottom, s.borderStyle.bottom},
+		{checkUnsetF(s.borderWidth.left, 0), s.borderColor.left, s.borderStyle.left},
+	}
+	for i := range sides {
+		if sides[i].color == nil {
+			sides[i].color = textColor
+		}
+		if sides[i].style == unset {
+			sides[i].style = BorderSolid
+		}
+	}
+	return sides
+}
+
+// drawBorder draws the border inside the box at x, y
+func drawBorder(ctx canvas, x, y, w, h float32, s Styles) {
+	sides := borderSides(s)
+	if sides[0].width <= 0 && sides[1].width <= 0 && sides[2].width <= 0 && sides[3].width <= 0 {
+		return
+	}
+	r := radii(s, w, h)
+
+	// the same solid border on every side is a single stroke, which joins nicely in the corners
+	if sides[0] == sides[1] && sides[0] == sides[2] && sides[0] == sides[3] && sides[0].style == BorderSolid {
+		half := sides[0].width / 2
+		ctx.BeginPath()
+		roundedRect(ctx, x+half, y+half, w-2*half, h-2*half, insetRadii(r, half))
+		ctx.SetStrokeColor(colorToNanoColor(sides[0].color))
+		ctx.SetStrokeWidth(sides[0].width)
+		ctx.Stroke()
+		return
+	}
+
+	for i, side := range sides {
+		if side.width > 0 {
+			drawBorderSide(ctx, x, y, w, h, r, i, side)
+		}
+	}
+}
+
+// drawBorderSide draws one side of the border, along with half of the rounded corners at both ends.
+// The sides are numbered clockwise from the top, and drawn clockwise.
+func drawBorderSide(ctx canvas, x, y, w, h float32, r [4]float32, i int, side borderSide) {
+	var startX, startY, dirX, dirY, length float32
+	switch i {
+	case 0:
+		startX, startY, dirX, dirY, length = x, y, 1, 0, w
+	case 1:
+		startX, startY, dirX, dirY, length = x+w, y, 0, 1, h
+	case 2:
+		startX, startY, dirX, dirY, length = x+w, y+h, -1, 0, w
+	case 3:
+		startX, startY, dirX, dirY, length = x, y+h, 0, -1, h
+	}
+	// the inside of the box is to the right of the direction of the side
+	normalX, normalY := -dirY, dirX
+	// angle from the center of the corner at the start of the side towards the previous side
+	angle := float32(math.Pi) + float32(i)*math.Pi/2
+
+	startRadius, endRadius := r[i], r[(i+1)%4]
+	half := side.width / 2
+	col := colorToNanoColor(side.color)
+
+	// corners that are too small to be round are drawn by letting the line go into the corner
+	lineStart, lineEnd := float32(0), length
+	if startRadius > half {
+		lineStart = startRadius
+	}
+	if endRadius > half {
+		lineEnd = length - endRadius
+	}
+	point := func(along float32) (float32, float32) {
+		return startX + dirX*along + normalX*half, startY + dirY*along + normalY*half
+	}
+
+	ctx.BeginPath()
+	if startRadius > half {
+		cx := startX + (dirX+normalX)*startRadius
+		cy := startY + (dirY+normalY)*startRadius
+		ctx.Arc(cx, cy, startRadius-half, angle+math.Pi/4, angle+math.Pi/2, nanovgo.Clockwise)
+	}
+	if side.style == BorderSolid {
+		if startRadius <= half {
+			ctx.MoveTo(point(lineStart))
+		}
+		ctx.LineTo(point(lineEnd))
+	}
+	if endRadius > half {
+		cx := startX + dirX*(length-endRadius) + normalX*endRadius
+		cy := startY + dirY*(length-endRadius) + normalY*endRadius
+		if side.style != BorderSolid {
+			ctx.MoveTo(point(lineEnd))
+		}
+		ctx.Arc(cx, cy, endRadius-half, angle+math.Pi/2, angle+3*math.Pi/4, nanovgo.Clockwise)
+	}
+	ctx.SetStrokeColor(col)
+	ctx.SetStrokeWidth(side.width)
+	ctx.Stroke()
+
+	switch side.style {
+	case BorderDashed:
+		drawDashes(ctx, point, lineStart, lineEnd, side.width, col)
+	case BorderDotted:
+		drawDots(ctx, point, lineStart, lineEnd, side.width, col)
+	}
+}
+
+// drawDashes draws dashes three times as long as they are wide between start and end,
+// stretched to begin and end with a whole dash
+func drawDashes(ctx canvas, point func(along float32) (float32, float32), start, end, width float32, col nanovgo.Color) {
+	dash, gap := 3*width, 2*width
+	length := end - start
+	count := float32(math.Max(1, math.Round(float64((length+gap)/(dash+gap)))))
+	scale := length / (count*dash + (count-1)*gap)
+	dash, gap = dash*scale, gap*scale
+
+	ctx.BeginPath()
+	for along := start; along < end-0.01; along += dash + gap {
+		ctx.MoveTo(point(along))
+		ctx.LineTo(point(minF(along+dash, end)))
+	}
+	ctx.SetStrokeColor(col)
+	ctx.SetStrokeWidth(width)
+	ctx.Stroke()
+}
+
+// drawDots draws round dots as wide as the border between start and end, with equal space between them
+func drawDots(ctx canvas, point func(along float32) (float32, float32), start, end, width float32, col nanovgo.Color) {
+	length := end - start - width
+	if length < 0 {
+		return
+	}
+	count := int(length/(2*width)) + 1
+	spacing := float32(0)
+	if count > 1 {
+		spacing = length / float32(count-1)
+	}
+
+	ctx.BeginPath()
+	for i := 0; i < count; i++ {
+		cx, cy := point(start + width/2 + float32(i)*spacing)
+		ctx.Circle(cx, cy, width/2)
+	}
+	ctx.SetFillColor(col)
+	ctx.Fill()
+}
@@ -0,0 +1,62 @@
+package goui
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/kjk/flex"
+)
+
+var buttonStyles = NewStyles().
+	Color(Token("text.secondary")).
+	Background(Token("surface")).
+	Padding(EdgeVertical, 8).
+	Padding(EdgeHorizontal, 16).
+	BorderRadius(4).
+	TextAlign(TextCenter).
+	Hover(NewStyles().Background(Token("surface.hover"))).
+	Focus(NewStyles().Color(Token("text.primary"))).
+	Active(NewStyles().Background(Token("surface.active"))).
+	Disabled(NewStyles().
+		Color(Token("text.disabled")).
+		Background(Token("surface.disabled")))
+
+func (g *gui) Button(text string) *Handle {
+	return g.addWidget(&buttonWidget{text: text})
+}
+
+type buttonWidget struct {
+	text string
+}
+
+func (w *buttonWidget) defaultStyles() Styles {
+	return buttonStyles
+}
+
+func (w *buttonWidget) render(ctx canvas, parentX, parentY float32, l *flex.Node, s Styles) {
+	drawRect(ctx, parentX, parentY, l, s)
+	drawText(ctx, w.text, parentX, parentY, l, s)
+}
+
+// activateFocused clicks the focused button when enter or space is pressed
+func (g *gui) activateFocused(ev KeyEvent) bool {
+	if g.focused == nil || ev.Action != Press {
+		return false
+	}
+	if _, ok := g.focused.widget.(*buttonWidget); !ok {
+		return false
+	}
+	if ev.Key != glfw.KeyEnter && ev.Key != glfw.KeyKPEnter && ev.Key != glfw.KeySpace {
+		return false
+	}
+
+	x, y := g.focused.transform.apply(g.focused.x+g.focused.width/2, g.focused.y+g.focused.height/2)
+	g.dispatchClick(g.focused, ClickEvent{
+		Button: MouseButtonLeft,
+		X:      float64(x),
+		Y:      float64(y),
+		Ctrl:   ev.Ctrl,
+		Shift:  ev.Shift,
+		Alt:    ev.Alt,
+		Super:  ev.Super,
+	})
+	return true
+}
@@ -0,0 +1,123 @@
+package goui
+
+import (
+	"image"
+
+	"github.com/shibukawa/nanovgo"
+)
+
+// canvas is what widgets draw on. The method set mirrors nanovgo, so the window uses a nanovgo context
+// and RenderToImage uses the software renderer in softcanvas.go.
+type canvas interface {
+	Save()
+	Restore()
+	SetGlobalAlpha(alpha float32)
+	Translate(x, y float32)
+	Rotate(angle float32)
+	Scale(x, y float32)
+	IntersectScissor(x, y, w, h float32)
+
+	BeginPath()
+	MoveTo(x, y float32)
+	LineTo(x, y float32)
+	BezierTo(c1x, c1y, c2x, c2y, x, y float32)
+	Arc(cx, cy, r, a0, a1 float32, dir nanovgo.Direction)
+	ClosePath()
+	PathWinding(winding nanovgo.Winding)
+	Rect(x, y, w, h float32)
+	RoundedRect(x, y, w, h, r float32)
+	Circle(cx, cy, r float32)
+	SetFillColor(color nanovgo.Color)
+	SetFillPaint(p paint)
+	SetStrokeColor(color nanovgo.Color)
+	SetStrokeWidth(width float32)
+	Fill()
+	Stroke()
+
+	CreateFontFromMemory(name string, data []byte, freeData uint8) int
+	SetFontFace(font string)
+	SetFontSize(size float32)
+	SetTextAlign(align nanovgo.Align)
+	Text(x, y float32, str string) float32
+	TextBounds(x, y float32, str string) (float32, []float32)
+	TextMetrics() (float32, float32, float32)
+	TextBreakLines(str string, breakRowWidth float32) []nanovgo.TextRow
+	TextGlyphPositions(x, y float32, str string) []nanovgo.GlyphPosition
+
+	CreateImageFromGoImage(imageFlag nanovgo.ImageFlags, img image.Image) int
+	DeleteImage(img int)
+}
+
+type paintKind int
+
+const (
+	paintImage paintKind = iota
+	paintBoxGradient
+	paintLinearGradient
+	paintRadialGradient
+)
+
+// paint describes how a path is filled when it is not filled with a single color.
+// nanovgo.Paint can't be read outside of nanovgo, so every backend converts this one itself.
+type paint struct {
+	kind paintKind
+
+	x, y, width, height float32
+	image               int
+	alpha               float32
+
+	radius, feather float32
+	inner, outer    nanovgo.Color
+
+	endX, endY  float32 // end of a linear gradient, which starts at x, y
+	innerRadius float32 // of a radial gradient, which ends at radius
+}
+
+// imagePattern fills with the image stretched over the given rectangle
+func imagePattern(x, y, width, height float32, image int, alpha float32) paint {
+	return paint{kind: paintImage, x: x, y: y, width: width, height: height, image: image, alpha: alpha}
+}
+
+// boxGradient goes from the inner color inside the rounded rectangle to the outer color outside of it,
+// in a band as wide as the feather
+func boxGradient(x, y, width, height, radius, feather float32, inner, outer nanovgo.Color) paint {
+	return paint{
+		kind:    paintBoxGradient,
+		x:       x,
+		y:       y,
+		width:   width,
+		height:  height,
+		radius:  radius,
+		feather: feather,
+		inner:   inner,
+		outer:   outer,
+	}
+}
+
+// linearGradient goes from the inner color at the start to the outer color at the end
+func linearGradient(startX, startY, endX, endY float32, inner, outer nanovgo.Color) paint {
+	return paint{kind: paintLinearGradient, x: startX, y: startY, endX: endX, endY: endY, inner: inner, outer: outer}
+}
+
+// radialGradient goes from the inner color at the inner radius around the center to the outer color at the radius
+func radialGradient(cx, cy, innerRadius, radius float32, inner, outer nanovgo.Color) paint {
+	return paint{kind: paintRadialGradient, x: cx, y: cy, innerRadius: innerRadius, radius: radius, inner: inner, outer: outer}
+}
+
+// nanovgoCanvas draws with OpenGL through nanovgo
+type nanovgoCanvas struct {
+	*nanovgo.Context
+}
+
+func (c nanovgoCanvas) SetFillPaint(p paint) {
+	switch p.kind {
+	case paintImage:
+		c.Context.SetFillPaint(nanovgo.ImagePattern(p.x, p.y, p.width, p.height, 0, p.image, p.alpha))
+	case paintBoxGradient:
+		c.Context.SetFillPaint(nanovgo.BoxGradient(p.x, p.y, p.width, p.height, p.radius, p.feather, p.inner, p.outer))
+	case paintLinearGradient:
+		c.Context.SetFillPaint(nanovgo.LinearGradient(p.x, p.y, p.endX, p.endY, p.inner, p.outer))
+	case paintRadialGradient:
+		c.Context.SetFillPaint(nanovgo.RadialGradient(p.x, p.y, p.innerRadius, p.radius, p.inner, p.outer))
+	}
+}
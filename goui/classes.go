@@ -0,0 +1,45 @@
+package goui
+
+// Stylesheet provides named styles for Handle.Class, see the css package for stylesheets written in css
+type Stylesheet interface {
+	// Class returns the styles of the class with the given name, or false if there is no such class
+	Class(name string) (Styles, bool)
+}
+
+// ReloadableStylesheet is a stylesheet that was loaded from a file. In Debug mode the file is watched
+// and the stylesheet is reloaded when it changes.
+type ReloadableStylesheet interface {
+	Stylesheet
+	// Path returns the file the stylesheet was loaded from, or "" if it wasn't loaded from a file
+	Path() string
+	// Reload parses the file again. On errors the stylesheet keeps the classes that could still be read.
+	Reload() error
+}
+
+// Stylesheet makes the classes of the stylesheet available to the widgets of the current render.
+// Call it on every render, usually at the start. Later stylesheets take precedence over earlier ones.
+func (g *gui) Stylesheet(sheet Stylesheet) {
+	g.stylesheets = append(g.stylesheets, sheet)
+	if reloadable, ok := sheet.(ReloadableStylesheet); ok && g.watcher != nil {
+		g.watchStylesheet(reloadable)
+	}
+}
+
+// classStyles combines the styles of the classes of a widget below its own styles
+func (g *gui) classStyles(w *widgetContainer) Styles {
+	styles := []Styles{NewStyles()}
+	for _, name := range w.handle.classes {
+		found := false
+		for _, sheet := range g.stylesheets {
+			if s, ok := sheet.Class(name); ok {
+				styles = append(styles, s)
+				found = true
+			}
+		}
+		if !found && !g.missingClasses[name] {
+			logWarning("unknown class", name)
+			g.missingClasses[name] = true
+		}
+	}
+	return CombineStyles(append(styles, w.handle.styles)...)
+}
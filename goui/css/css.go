@@ -0,0 +1,273 @@
+// Package css parses stylesheets written in a subset of css into goui styles.
+//
+// Every rule sets the styles of one or more classes, which widgets use with goui.Handle.Class:
+//
+//	:root {
+//		--accent: #3a7bd5;
+//	}
+//	.menu-item {
+//		padding: 6px 15px;
+//		border-radius: 5px;
+//		transition: background 150ms ease-out;
+//	}
+//	.menu-item:hover {
+//		background: var(--accent);
+//	}
+//
+// Selectors are single classes, optionally with one of the :hover, :focus, :active and :disabled
+// pseudo-classes, and :root for custom properties. Custom properties can also be set in the rule that
+// uses them. The properties are named like in css and set the goui styles of the same name. Properties
+// that goui doesn't have, like transform-origin in pixels, are reported as errors. The inherited
+// properties color, font-family, font-size and text-align can be set to initial. Colors of the theme of
+// the UI are used with token("text.primary").
+package css
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"../../goui"
+)
+
+// Stylesheet holds the styles of the classes of a parsed stylesheet
+type Stylesheet struct {
+	path    string
+	classes map[string]goui.Styles
+}
+
+// Class returns the styles of the class, it implements goui.Stylesheet
+func (s *Stylesheet) Class(name string) (goui.Styles, bool) {
+	styles, ok := s.classes[name]
+	return styles, ok
+}
+
+// Classes returns the names of all classes in the stylesheet
+func (s *Stylesheet) Classes() []string {
+	names := make([]string, 0, len(s.classes))
+	for name := range s.classes {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Path returns the file the stylesheet was loaded from, or "" if it was parsed from a string
+func (s *Stylesheet) Path() string {
+	return s.path
+}
+
+// Reload parses the file of the stylesheet again, it implements goui.ReloadableStylesheet. When the
+// file can't be read the classes are kept, on other errors only the valid parts are used like in Load.
+func (s *Stylesheet) Reload() error {
+	if s.path == "" {
+		return nil
+	}
+	reloaded, err := Load(s.path)
+	if reloaded != nil {
+		s.classes = reloaded.classes
+	}
+	return err
+}
+
+// Parse parses a stylesheet. When there are errors, the stylesheet is still returned without the rules
+// and declarations that were invalid, along with an ErrorList.
+func Parse(src string) (*Stylesheet, error) {
+	return parse("", src)
+}
+
+// Load reads and parses the stylesheet at path, errors contain the path
+func Load(path string) (*Stylesheet, error) {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sheet, err := parse(path, string(src))
+	sheet.path = path
+	return sheet, err
+}
+
+// Error is a problem at a position in a stylesheet
+type Error struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("%v:%v: %v", e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("%v:%v:%v: %v", e.File, e.Line, e.Column, e.Message)
+}
+
+// ErrorList is all errors of a stylesheet, sorted by their position
+type ErrorList []*Error
+
+func (l ErrorList) Error() string {
+	messages := make([]string, len(l))
+	for i, e := range l {
+		messages[i] = e.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+type errorList struct {
+	file   string
+	errors ErrorList
+}
+
+func (l *errorList) add(line, col int, format string, args ...interface{}) {
+	l.errors = append(l.errors, &Error{File: l.file, Line: line, Column: col, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *errorList) at(t token, format string, args ...interface{}) {
+	l.add(t.line, t.col, format, args...)
+}
+
+// err returns the errors sorted by their position, since the values are checked after all rules are read
+func (l *errorList) err() error {
+	if len(l.errors) == 0 {
+		return nil
+	}
+	sort.SliceStable(l.errors, func(i, j int) bool {
+		a, b := l.errors[i], l.errors[j]
+		return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+	})
+	return l.errors
+}
+
+// valueError is returned by the value parsers, the position is added when it is reported
+type valueError struct {
+	at      token
+	message string
+}
+
+func (e *valueError) Error() string {
+	return e.message
+}
+
+func errorAt(t token, format string, args ...interface{}) error {
+	return &valueError{at: t, message: fmt.Sprintf(format, args...)}
+}
+
+func parse(file, src string) (*Stylesheet, error) {
+	errors := &errorList{file: file}
+	p := &parser{tokens: tokenize(src, errors), errors: errors}
+	rules := p.parseSheet()
+
+	// custom properties of :root are visible everywhere
+	global := variables{}
+	for _, r := range rules {
+		if !r.hasRoot() {
+			continue
+		}
+		for _, d := range r.declarations {
+			if d.custom() {
+				global[d.name.text] = d.value
+			}
+		}
+	}
+
+	sheet := &Stylesheet{classes: map[string]goui.Styles{}}
+	for _, r := range rules {
+		vars := global.with(r.declarations)
+		styles := goui.NewStyles()
+		for _, d := range r.declarations {
+			if d.custom() {
+				continue
+			}
+			if r.hasRoot() {
+				errors.at(d.name, "only custom properties like --%v can be set in :root", d.name.text)
+				continue
+			}
+			s, ok := evaluate(d, vars, errors)
+			if ok {
+				styles = goui.CombineStyles(styles, s)
+			}
+		}
+
+		for _, sel := range r.selectors {
+			if sel.root {
+				continue
+			}
+			existing, ok := sheet.classes[sel.class]
+			if !ok {
+				existing = goui.NewStyles()
+			}
+			sheet.classes[sel.class] = goui.CombineStyles(existing, sel.variant(styles))
+		}
+	}
+	return sheet, errors.err()
+}
+
+// evaluate returns the styles set by a declaration
+func evaluate(d declaration, vars variables, errors *errorList) (goui.Styles, bool) {
+	name := strings.ToLower(d.name.text)
+	prop, ok := properties[name]
+	if !ok {
+		if suggestion := closestProperty(name); suggestion != "" {
+			errors.at(d.name, "unknown property %v, did you mean %v?", d.name.text, suggestion)
+		} else {
+			errors.at(d.name, "unknown property %v", d.name.text)
+		}
+		return goui.Styles{}, false
+	}
+
+	tokens, err := vars.substitute(d.value, 0)
+	if err == nil {
+		var s goui.Styles
+		s, err = prop(&values{tokens: tokens, property: d.name})
+		if err == nil {
+			return s, true
+		}
+	}
+	if e, ok := err.(*valueError); ok {
+		errors.at(e.at, "%v: %v", name, e.message)
+	} else {
+		errors.at(d.name, "%v: %v", name, err)
+	}
+	return goui.Styles{}, false
+}
+
+// closestProperty returns the known property that is closest to the unknown one, if it is close enough to be a typo
+func closestProperty(name string) string {
+	best, bestDistance := "", 3
+	for known := range properties {
+		if d := editDistance(name, known); d < bestDistance || (d == bestDistance && known < best) {
+			best, bestDistance = known, d
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,235 @@
+package css
+
+import (
+	"image/color"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"../../goui"
+)
+
+// parsed returns the styles a stylesheet has for a class with the given styles, which are combined on top of
+// the empty styles once for the rule and once for the class
+func parsed(s goui.Styles) goui.Styles {
+	return goui.CombineStyles(goui.NewStyles(), goui.CombineStyles(goui.NewStyles(), s))
+}
+
+func checkClass(t *testing.T, sheet *Stylesheet, class string, want goui.Styles) {
+	t.Helper()
+	got, ok := sheet.Class(class)
+	if !ok {
+		t.Errorf("no class %v", class)
+		return
+	}
+	if !reflect.DeepEqual(got, parsed(want)) {
+		t.Errorf("class %v is\n%+v\nexpected\n%+v", class, got, parsed(want))
+	}
+}
+
+func TestSelectors(t *testing.T) {
+	red := color.NRGBA{255, 0, 0, 255}
+	blue := color.NRGBA{0, 0, 255, 255}
+	tests := []struct {
+		name  string
+		src   string
+		class string
+		want  goui.Styles
+	}{
+		{"class", ".a { color: red }", "a", goui.NewStyles().Color(red)},
+		{"list", ".a, .b { color: red }", "b", goui.NewStyles().Color(red)},
+		{"hover", ".a:hover { color: red }", "a", goui.NewStyles().Hover(goui.NewStyles().Color(red))},
+		{"focus", ".a:focus { color: red }", "a", goui.NewStyles().Focus(goui.NewStyles().Color(red))},
+		{"active", ".a:active { color: red }", "a", goui.NewStyles().Active(goui.NewStyles().Color(red))},
+		{"disabled", ".a:disabled { color: red }", "a", goui.NewStyles().Disabled(goui.NewStyles().Color(red))},
+		{"later rules win", ".a { color: red; width: 5px } .a { color: blue }", "a", goui.NewStyles().Color(blue).Width(5)},
+		{"root variable", ":root { --c: blue } .a { color: var(--c) }", "a", goui.NewStyles().Color(blue)},
+		{"rule variable", ".a { --c: blue; color: var(--c) }", "a", goui.NewStyles().Color(blue)},
+		{"variable fallback", ".a { color: var(--missing, red) }", "a", goui.NewStyles().Color(red)},
+		{"comments", "/* .b { color: blue } */ .a { /* x */ color: red }", "a", goui.NewStyles().Color(red)},
+	}
+	for _, test := range tests {
+		sheet, err := Parse(test.src)
+		if err != nil {
+			t.Errorf("%v: %v", test.name, err)
+			continue
+		}
+		checkClass(t, sheet, test.class, test.want)
+	}
+}
+
+func TestValues(t *testing.T) {
+	red := color.NRGBA{255, 0, 0, 255}
+	blue := color.NRGBA{0, 0, 255, 255}
+	tests := []struct {
+		declaration string
+		want        goui.Styles
+	}{
+		// colors
+		{"color: red", goui.NewStyles().Color(red)},
+		{"color: RED", goui.NewStyles().Color(red)},
+		{"color: #f00", goui.NewStyles().Color(red)},
+		{"color: #f008", goui.NewStyles().Color(color.NRGBA{255, 0, 0, 0x88})},
+		{"color: #3a7bd5", goui.NewStyles().Color(color.NRGBA{0x3a, 0x7b, 0xd5, 255})},
+		{"color: #3a7bd580", goui.NewStyles().Color(color.NRGBA{0x3a, 0x7b, 0xd5, 0x80})},
+		{"color: rgb(255, 0, 0)", goui.NewStyles().Color(red)},
+		{"color: rgba(0, 0, 255, 50%)", goui.NewStyles().Color(color.NRGBA{0, 0, 255, 128})},
+		{"color: rgb(0 0 255 / 0.5)", goui.NewStyles().Color(color.NRGBA{0, 0, 255, 128})},
+		{"color: hsl(120, 100%, 25%)", goui.NewStyles().Color(color.NRGBA{0, 128, 0, 255})},
+		{"background-color: transparent", goui.NewStyles().Background(color.NRGBA{})},
+		{`color: token("text.primary")`, goui.NewStyles().Color(goui.Token("text.primary"))},
+		{`border: 1px solid token("accent")`, goui.NewStyles().Border(goui.EdgeAll, 1, goui.Token("accent")).BorderStyle(goui.EdgeAll, goui.BorderSolid)},
+
+		// lengths and sizes
+		{"width: 10px", goui.NewStyles().Width(10)},
+		{"width: 50%", goui.NewStyles().WidthPct(50)},
+		{"height: 0", goui.NewStyles().Height(0)},
+		{"margin: 1px", goui.NewStyles().Margin(goui.EdgeAll, 1)},
+		{"margin: 1px 2px", goui.NewStyles().Margin(goui.EdgeVertical, 1).Margin(goui.EdgeHorizontal, 2)},
+		{"padding: 1px 2px 3px 4px", goui.NewStyles().
+			Padding(goui.EdgeTop, 1).Padding(goui.EdgeRight, 2).Padding(goui.EdgeBottom, 3).Padding(goui.EdgeLeft, 4)},
+		{"padding-left: 3px", goui.NewStyles().Padding(goui.EdgeLeft, 3)},
+		{"border-radius: 4px", goui.NewStyles().BorderRadius(4)},
+		{"border: 2px dashed blue", goui.NewStyles().Border(goui.EdgeAll, 2, blue).BorderStyle(goui.EdgeAll, goui.BorderDashed)},
+		{"flex-grow: 2", goui.NewStyles().FlexGrow(2)},
+		{"opacity: 50%", goui.NewStyles().Opacity(0.5)},
+
+		// gradients
+		{"background: linear-gradient(red, blue)", goui.NewStyles().LinearGradient(180, goui.Stop(0, red), goui.Stop(1, blue))},
+		{"background: linear-gradient(90deg, red, blue)", goui.NewStyles().LinearGradient(90, goui.Stop(0, red), goui.Stop(1, blue))},
+		{"background: linear-gradient(to right, red, blue 40%, red)",
+			goui.NewStyles().LinearGradient(90, goui.Stop(0, red), goui.Stop(0.4, blue), goui.Stop(1, red))},
+		{"background: radial-gradient(red, blue)", goui.NewStyles().RadialGradient(goui.Stop(0, red), goui.Stop(1, blue))},
+		{"background: linear-gradient(red, blue) red",
+			goui.NewStyles().LinearGradient(180, goui.Stop(0, red), goui.Stop(1, blue)).Background(red)},
+		{"background: none", goui.NewStyles().Background(color.NRGBA{}).NoBackgroundPaint()},
+
+		// shadows
+		{"box-shadow: 1px 2px", goui.NewStyles().BoxShadow(1, 2, 0, 0, color.NRGBA{0, 0, 0, 255})},
+		{"box-shadow: 1px 2px 3px 4px red, inset 0 0 2px blue",
+			goui.NewStyles().BoxShadow(1, 2, 3, 4, red).InsetBoxShadow(0, 0, 2, 0, blue)},
+		{"box-shadow: none", goui.NewStyles().NoBoxShadow()},
+
+		// transforms
+		{"transform: translate(10px, 5px)", goui.NewStyles().Translate(10, 5)},
+		{"transform: translateY(5px)", goui.NewStyles().Translate(0, 5)},
+		{"transform: rotate(0.25turn)", goui.NewStyles().Rotate(90)},
+		{"transform: scale(2)", goui.NewStyles().Scale(2, 2)},
+		{"transform: scale(2) scaleX(3) rotate(10deg) rotate(20deg)", goui.NewStyles().Rotate(30).Scale(6, 2)},
+		{"transform: none", goui.NewStyles().Translate(0, 0).Rotate(0).Scale(1, 1)},
+		{"rotate: 45deg", goui.NewStyles().Rotate(45)},
+		{"transform-origin: left top", goui.NewStyles().TransformOrigin(0, 0)},
+
+		// inherited properties
+		{"color: initial", goui.NewStyles().Initial(goui.InheritedColor)},
+		{"font-size: initial", goui.NewStyles().Initial(goui.InheritedFontSize)},
+		{"color: red; color: initial", goui.NewStyles().Initial(goui.InheritedColor)},
+		{"color: initial; color: red", goui.NewStyles().Color(red)},
+	}
+	for _, test := range tests {
+		sheet, err := Parse(".a { " + test.declaration + " }")
+		if err != nil {
+			t.Errorf("%v: %v", test.declaration, err)
+			continue
+		}
+		got, _ := sheet.Class("a")
+		if !reflect.DeepEqual(got, parsed(test.want)) {
+			t.Errorf("%v is\n%+v\nexpected\n%+v", test.declaration, got, parsed(test.want))
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{".a { colr: red }", "1:6: unknown property colr, did you mean color?"},
+		{".a {\n\tbackround: red;\n}", "2:2: unknown property backround, did you mean background?"},
+		{".a { paddin-top: 1px }", "1:6: unknown property paddin-top, did you mean padding-top?"},
+		{".a { banana: 1 }", "1:6: unknown property banana"},
+		{".a { width: 10em }", "1:13: width: expected a length in px or %, found 10em"},
+		{".a { color: #12 }", "1:13: color: invalid color #12, expected #rgb, #rgba, #rrggbb or #rrggbbaa"},
+		{".a { color: bleu }", "1:13: color: unknown color bleu"},
+		{".a { padding: 1px 2px 3px 4px 5px }", "1:6: padding: expected 1 to 4 values, found 5"},
+		{".a { transform: skew(10deg) }", "1:17: transform: unsupported transform skew(, expected translate, scale or rotate"},
+		{".a { color: var(--nope) }", "1:17: color: custom property --nope is not defined"},
+		{".a { color: token(1px) }", "1:19: color: expected a name, found 1px"},
+		{".a { width: initial }", "1:13: width: expected a length in px or %, found initial"},
+		{".a { color: initial red }", "1:13: color: unknown color initial"},
+		{":root { color: red }", "1:9: only custom properties like --color can be set in :root"},
+		{".a .b { color: red }", "1:4: unexpected . in selector, only .class, .class:hover and :root are supported"},
+		{".a:visited { color: red }", "1:4: unsupported pseudo-class :visited, only :hover, :focus, :active and :disabled are supported"},
+		{"#a { color: red }", "1:1: unsupported selector #a, only .class, .class:hover and :root are supported"},
+	}
+	for _, test := range tests {
+		_, err := Parse(test.src)
+		if err == nil {
+			t.Errorf("%q: no error, expected %v", test.src, test.want)
+			continue
+		}
+		if got := err.Error(); got != test.want {
+			t.Errorf("%q: error\n%v\nexpected\n%v", test.src, got, test.want)
+		}
+	}
+}
+
+func TestErrorsKeepValidParts(t *testing.T) {
+	sheet, err := Parse(`
+.a { colr: red; width: 5px }
+.b:visited { color: red }
+.c { height: 10em; color: red }
+`)
+	errors, ok := err.(ErrorList)
+	if !ok || len(errors) != 3 {
+		t.Fatalf("expected 3 errors, got %v", err)
+	}
+	for i, line := range []int{2, 3, 4} {
+		if errors[i].Line != line {
+			t.Errorf("error %v is on line %v, expected %v", i, errors[i].Line, line)
+		}
+	}
+
+	checkClass(t, sheet, "a", goui.NewStyles().Width(5))
+	if _, ok := sheet.Class("b"); ok {
+		t.Errorf("the rule with an invalid selector was used")
+	}
+	checkClass(t, sheet, "c", goui.NewStyles().Color(color.NRGBA{255, 0, 0, 255}))
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "css")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "main.css")
+	if err := ioutil.WriteFile(path, []byte(".a {\n  width: 1em;\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sheet, err := Load(path)
+	if err == nil || err.Error() != path+":2:10: width: expected a length in px or %, found 1em" {
+		t.Errorf("error %v doesn't have the path and position", err)
+	}
+	if sheet.Path() != path {
+		t.Errorf("path %v", sheet.Path())
+	}
+
+	if err := ioutil.WriteFile(path, []byte(".a { width: 2px }"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := sheet.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	checkClass(t, sheet, "a", goui.NewStyles().Width(2))
+
+	os.Remove(path)
+	if err := sheet.Reload(); err == nil || !strings.Contains(err.Error(), "main.css") {
+		t.Errorf("reloading a missing file gives %v", err)
+	}
+	checkClass(t, sheet, "a", goui.NewStyles().Width(2))
+}
@@ -0,0 +1,306 @@
+package css
+
+import (
+	"strings"
+
+	"../../goui"
+)
+
+type rule struct {
+	selectors    []selector
+	declarations []declaration
+}
+
+func (r rule) hasRoot() bool {
+	for _, sel := range r.selectors {
+		if sel.root {
+			return true
+		}
+	}
+	return false
+}
+
+type selector struct {
+	root  bool
+	class string
+	state string // hover, focus, active or disabled, or empty
+}
+
+// variant returns the styles of a rule for the state of the selector
+func (sel selector) variant(styles goui.Styles) goui.Styles {
+	switch sel.state {
+	case "hover":
+		return goui.NewStyles().Hover(styles)
+	case "focus":
+		return goui.NewStyles().Focus(styles)
+	case "active":
+		return goui.NewStyles().Active(styles)
+	case "disabled":
+		return goui.NewStyles().Disabled(styles)
+	}
+	return styles
+}
+
+type declaration struct {
+	name  token
+	value []token
+}
+
+// custom returns if the declaration sets a custom property like --accent
+func (d declaration) custom() bool {
+	return strings.HasPrefix(d.name.text, "--")
+}
+
+// parser reads rules from the tokens, skipping the parts with errors
+type parser struct {
+	tokens []token
+	pos    int
+	errors *errorList
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseSheet() []rule {
+	var rules []rule
+	for p.peek().kind != tokenEOF {
+		if t := p.peek(); t.isDelim("@") {
+			p.next()
+			p.errors.at(t, "at-rules like @%v are not supported", p.peek().text)
+			p.skipRule()
+			continue
+		}
+
+		selectors, ok := p.parseSelectors()
+		if !ok {
+			p.skipRule()
+			continue
+		}
+		rules = append(rules, rule{selectors: selectors, declarations: p.parseBlock()})
+	}
+	return rules
+}
+
+// parseSelectors reads the comma separated selectors of a rule and the opening brace
+func (p *parser) parseSelectors() ([]selector, bool) {
+	var selectors []selector
+	for {
+		sel, ok := p.parseSelector()
+		if !ok {
+			return nil, false
+		}
+		selectors = append(selectors, sel)
+
+		t := p.next()
+		if t.isDelim("{") {
+			return selectors, true
+		}
+		if !t.isDelim(",") {
+			p.errors.at(t, "unexpected %v in selector, only .class, .class:hover and :root are supported", t)
+			return nil, false
+		}
+	}
+}
+
+func (p *parser) parseSelector() (selector, bool) {
+	var sel selector
+	t := p.next()
+	switch {
+	case t.isDelim("."):
+		name := p.next()
+		if name.kind != tokenIdent {
+			p.errors.at(name, "expected a class name after ., found %v", name)
+			return sel, false
+		}
+		sel.class = name.text
+	case t.isDelim(":") && p.peek().is(tokenIdent, "root"):
+		p.next()
+		sel.root = true
+		return sel, true
+	default:
+		p.errors.at(t, "unsupported selector %v, only .class, .class:hover and :root are supported", t)
+		return sel, false
+	}
+
+	if p.peek().isDelim(":") {
+		p.next()
+		state := p.next()
+		switch state.text {
+		case "hover", "focus", "active", "disabled":
+			sel.state = state.text
+		default:
+			p.errors.at(state, "unsupported pseudo-class :%v, only :hover, :focus, :active and :disabled are supported", state.text)
+			return sel, false
+		}
+	}
+	return sel, true
+}
+
+// parseBlock reads the declarations up to and including the closing brace
+func (p *parser) parseBlock() []declaration {
+	var declarations []declaration
+	for {
+		t := p.peek()
+		switch {
+		case t.kind == tokenEOF:
+			p.errors.at(t, "missing } at the end of the rule")
+			return declarations
+		case t.isDelim("}"):
+			p.next()
+			return declarations
+		case t.isDelim(";"):
+			p.next()
+		case t.kind == tokenIdent:
+			name := p.next()
+			if colon := p.peek(); !colon.isDelim(":") {
+				p.errors.at(colon, "expected : after %v, found %v", name.text, colon)
+				p.readValue()
+				continue
+			}
+			p.next()
+			value := p.readValue()
+			if len(value) == 0 {
+				p.errors.at(name, "missing value for %v", name.text)
+				continue
+			}
+			declarations = append(declarations, declaration{name: name, value: value})
+		default:
+			p.errors.at(t, "expected a property name, found %v", t)
+			p.next()
+			p.readValue()
+		}
+	}
+}
+
+// readValue reads the tokens up to the end of the declaration, without the semicolon or the closing brace.
+// A trailing !important is ignored, since there is nothing to take precedence over.
+func (p *parser) readValue() []token {
+	var value []token
+	depth := 0
+	for {
+		t := p.peek()
+		if t.kind == tokenEOF || (depth == 0 && (t.isDelim(";") || t.isDelim("}"))) {
+			break
+		}
+		p.next()
+		if t.kind == tokenFunction || t.isDelim("(") {
+			depth++
+		} else if t.isDelim(")") && depth > 0 {
+			depth--
+		}
+		value = append(value, t)
+	}
+
+	if n := len(value); n >= 2 && value[n-2].isDelim("!") && strings.EqualFold(value[n-1].text, "important") {
+		value = value[:n-2]
+	}
+	return value
+}
+
+// skipRule skips a rule with an invalid selector or an unsupported at-rule, including its block
+func (p *parser) skipRule() {
+	for {
+		t := p.next()
+		switch {
+		case t.kind == tokenEOF:
+			return
+		case t.isDelim(";"):
+			return
+		case t.isDelim("{"):
+			depth := 1
+			for depth > 0 {
+				t := p.next()
+				if t.kind == tokenEOF {
+					return
+				} else if t.isDelim("{") {
+					depth++
+				} else if t.isDelim("}") {
+					depth--
+				}
+			}
+			return
+		}
+	}
+}
+
+// variables maps the names of custom properties to their values
+type variables map[string][]token
+
+// with returns the variables with the custom properties of a rule added
+func (vars variables) with(declarations []declaration) variables {
+	combined := variables{}
+	for name, value := range vars {
+		combined[name] = value
+	}
+	for _, d := range declarations {
+		if d.custom() {
+			combined[d.name.text] = d.value
+		}
+	}
+	return combined
+}
+
+// substitute replaces every var(--name) and var(--name, fallback) with the value of the custom property
+func (vars variables) substitute(tokens []token, depth int) ([]token, error) {
+	var result []token
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if !t.is(tokenFunction, "var") {
+			result = append(result, t)
+			continue
+		}
+
+		end := closingParen(tokens, i)
+		if end < 0 {
+			return nil, errorAt(t, "var( is not closed")
+		}
+		args := tokens[i+1 : end]
+		if len(args) == 0 || args[0].kind != tokenIdent || !strings.HasPrefix(args[0].text, "--") {
+			return nil, errorAt(t, "var() needs the name of a custom property, like var(--accent)")
+		}
+		if depth > 16 {
+			return nil, errorAt(t, "custom property %v refers to itself", args[0].text)
+		}
+
+		value, ok := vars[args[0].text]
+		if !ok {
+			if len(args) < 3 || !args[1].isDelim(",") {
+				return nil, errorAt(args[0], "custom property %v is not defined", args[0].text)
+			}
+			value = args[2:]
+		}
+		value, err := vars.substitute(value, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value...)
+		i = end
+	}
+	return result, nil
+}
+
+// closingParen returns the index of the parenthesis that closes the function at start, or -1
+func closingParen(tokens []token, start int) int {
+	depth := 0
+	for i := start; i < len(tokens); i++ {
+		t := tokens[i]
+		if t.kind == tokenFunction || t.isDelim("(") {
+			depth++
+		} else if t.isDelim(")") {
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
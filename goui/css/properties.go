@@ -0,0 +1,814 @@
+package css
+
+import (
+	"image/color"
+	"strings"
+	"time"
+
+	"../../goui"
+)
+
+// property reads the value of a declaration and returns the styles it sets
+type property func(v *values) (goui.Styles, error)
+
+// properties maps the supported css properties to the goui styles they set
+var properties map[string]property
+
+func init() {
+	properties = map[string]property{
+		"width":      sizeProperty(goui.Styles.Width, goui.Styles.WidthPct),
+		"height":     sizeProperty(goui.Styles.Height, goui.Styles.HeightPct),
+		"min-width":  sizeProperty(goui.Styles.MinWidth, goui.Styles.MinWidthPct),
+		"min-height": sizeProperty(goui.Styles.MinHeight, goui.Styles.MinHeightPct),
+		"max-width":  sizeProperty(goui.Styles.MaxWidth, goui.Styles.MaxWidthPct),
+		"max-height": sizeProperty(goui.Styles.MaxHeight, goui.Styles.MaxHeightPct),
+		"margin":     edgesProperty(readMargin),
+		"padding":    edgesProperty(readPadding),
+
+		"position": keywordProperty([]string{"relative", "absolute"}, func(s goui.Styles, i int) goui.Styles {
+			return s.Position([]goui.Position{goui.PositionRelative, goui.PositionAbsolute}[i])
+		}),
+		"overflow": keywordProperty([]string{"visible", "hidden", "scroll", "auto"}, func(s goui.Styles, i int) goui.Styles {
+			return s.Overflow([]goui.Overflow{goui.OverflowVisible, goui.OverflowHidden, goui.OverflowScroll, goui.OverflowScroll}[i])
+		}),
+		"flex-direction": keywordProperty([]string{"row", "row-reverse", "column", "column-reverse"}, func(s goui.Styles, i int) goui.Styles {
+			return s.FlexDirection([]goui.FlexDirection{goui.Row, goui.RowReverse, goui.Column, goui.ColumnReverse}[i])
+		}),
+		"flex-wrap": keywordProperty([]string{"nowrap", "wrap", "wrap-reverse"}, func(s goui.Styles, i int) goui.Styles {
+			return s.Wrap([]goui.WrapType{goui.NoWrap, goui.Wrap, goui.WrapReverse}[i])
+		}),
+		"justify-content": keywordProperty([]string{"flex-start", "start", "center", "flex-end", "end", "space-between", "space-around"}, func(s goui.Styles, i int) goui.Styles {
+			return s.JustifyContent([]goui.Justify{goui.JustifyFlexStart, goui.JustifyFlexStart, goui.JustifyCenter, goui.JustifyFlexEnd, goui.JustifyFlexEnd, goui.JustifySpaceBetween, goui.JustifySpaceAround}[i])
+		}),
+		"align-items":   alignProperty(goui.Styles.AlignItems),
+		"align-content": alignProperty(goui.Styles.AlignContent),
+		"align-self":    alignProperty(goui.Styles.AlignSelf),
+		"flex-grow":     numberProperty(goui.Styles.FlexGrow),
+		"flex-shrink":   numberProperty(goui.Styles.FlexShrink),
+		"flex":          readFlex,
+
+		"font-family": single(func(v *values, s goui.Styles) (goui.Styles, error) {
+			name, err := v.str()
+			return s.FontFamily(name), err
+		}),
+		"font-size": single(func(v *values, s goui.Styles) (goui.Styles, error) {
+			px, err := v.length()
+			return s.FontSize(px), err
+		}),
+		"text-align": keywordProperty([]string{"left", "start", "center", "right", "end"}, func(s goui.Styles, i int) goui.Styles {
+			return s.TextAlign([]goui.TextAlign{goui.TextLeft, goui.TextLeft, goui.TextCenter, goui.TextRight, goui.TextRight}[i])
+		}),
+		"vertical-align": keywordProperty([]string{"top", "middle", "bottom"}, func(s goui.Styles, i int) goui.Styles {
+			return s.TextBaseline([]goui.TextBaseline{goui.TextTop, goui.TextMiddle, goui.TextBottom}[i])
+		}),
+		"line-height": numberProperty(goui.Styles.LineHeight),
+		"max-lines": single(func(v *values, s goui.Styles) (goui.Styles, error) {
+			t := v.peek()
+			lines, err := v.number()
+			if err == nil && (lines != float64(int(lines)) || lines < 0) {
+				err = errorAt(t, "expected a whole number of lines, found %v", t)
+			}
+			return s.MaxLines(int(lines)), err
+		}),
+		"white-space": keywordProperty([]string{"normal", "nowrap", "pre"}, func(s goui.Styles, i int) goui.Styles {
+			return s.WhiteSpace([]goui.WhiteSpace{goui.WhiteSpaceWrap, goui.WhiteSpaceNoWrap, goui.WhiteSpacePre}[i])
+		}),
+
+		"color":            colorProperty(goui.Styles.Color),
+		"background-color": colorProperty(goui.Styles.Background),
+		"background":       readBackground,
+		"background-image": readBackgroundImage,
+
+		"border":       readBorder(goui.EdgeAll),
+		"border-width": edgesProperty(readBorderWidth),
+		"border-color": edgesProperty(readBorderColor),
+		"border-style": edgesProperty(readBorderStyle),
+		"border-radius": cornersProperty(func(v *values, s goui.Styles, corner goui.Corner) (goui.Styles, error) {
+			px, err := v.length()
+			return s.CornerRadius(corner, px), err
+		}),
+
+		"box-shadow": readBoxShadow,
+		"object-fit": keywordProperty([]string{"fill", "contain", "cover", "none"}, func(s goui.Styles, i int) goui.Styles {
+			return s.ObjectFit([]goui.ObjectFit{goui.ObjectFitFill, goui.ObjectFitContain, goui.ObjectFitCover, goui.ObjectFitNone}[i])
+		}),
+		"opacity": single(func(v *values, s goui.Styles) (goui.Styles, error) {
+			if v.peek().unit == "%" {
+				pct, err := v.percentage()
+				return s.Opacity(pct / 100), err
+			}
+			alpha, err := v.number()
+			return s.Opacity(alpha), err
+		}),
+
+		"transform": readTransform,
+		"translate": readTranslate,
+		"scale":     readScale,
+		"rotate": single(func(v *values, s goui.Styles) (goui.Styles, error) {
+			degrees, err := v.angle()
+			return s.Rotate(degrees), err
+		}),
+		"transform-origin": readTransformOrigin,
+		"transition":       readTransition,
+		"outline":          readOutline,
+	}
+
+	inherited := map[string]goui.Inherited{
+		"color":       goui.InheritedColor,
+		"font-family": goui.InheritedFontFamily,
+		"font-size":   goui.InheritedFontSize,
+		"text-align":  goui.InheritedTextAlign,
+	}
+	for name, styles := range inherited {
+		properties[name] = inheritable(styles, properties[name])
+	}
+
+	for i, side := range []string{"top", "right", "bottom", "left"} {
+		edge := []goui.Edge{goui.EdgeTop, goui.EdgeRight, goui.EdgeBottom, goui.EdgeLeft}[i]
+		properties["margin-"+side] = edgeProperty(edge, readMargin)
+		properties["padding-"+side] = edgeProperty(edge, readPadding)
+		properties["border-"+side] = readBorder(edge)
+		properties["border-"+side+"-width"] = edgeProperty(edge, readBorderWidth)
+		properties["border-"+side+"-color"] = edgeProperty(edge, readBorderColor)
+		properties["border-"+side+"-style"] = edgeProperty(edge, readBorderStyle)
+	}
+	for i, corner := range []string{"top-left", "top-right", "bottom-right", "bottom-left"} {
+		corner, name := []goui.Corner{goui.CornerTopLeft, goui.CornerTopRight, goui.CornerBottomRight, goui.CornerBottomLeft}[i], corner
+		properties["border-"+name+"-radius"] = single(func(v *values, s goui.Styles) (goui.Styles, error) {
+			px, err := v.length()
+			return s.CornerRadius(corner, px), err
+		})
+	}
+}
+
+// single returns a property that reads one value
+func single(read func(v *values, s goui.Styles) (goui.Styles, error)) property {
+	return func(v *values) (goui.Styles, error) {
+		s, err := read(v, goui.NewStyles())
+		if err != nil {
+			return s, err
+		}
+		return s, v.end()
+	}
+}
+
+// inheritable returns a property that widgets take from their parent box, which can also be initial
+// to use the initial value instead
+func inheritable(styles goui.Inherited, prop property) property {
+	return func(v *values) (goui.Styles, error) {
+		if v.only("initial") {
+			return goui.NewStyles().Initial(styles), nil
+		}
+		return prop(v)
+	}
+}
+
+func sizeProperty(px, pct func(goui.Styles, float64) goui.Styles) property {
+	return single(func(v *values, s goui.Styles) (goui.Styles, error) {
+		value, percent, err := v.size()
+		if percent {
+			return pct(s, value), err
+		}
+		return px(s, value), err
+	})
+}
+
+func numberProperty(set func(goui.Styles, float64) goui.Styles) property {
+	return single(func(v *values, s goui.Styles) (goui.Styles, error) {
+		n, err := v.number()
+		return set(s, n), err
+	})
+}
+
+func colorProperty(set func(goui.Styles, color.Color) goui.Styles) property {
+	return single(func(v *values, s goui.Styles) (goui.Styles, error) {
+		c, err := v.color()
+		return set(s, c), err
+	})
+}
+
+// keywordProperty returns a property that is one of the keywords, set is called with its index
+func keywordProperty(keywords []string, set func(s goui.Styles, i int) goui.Styles) property {
+	return single(func(v *values, s goui.Styles) (goui.Styles, error) {
+		i, err := v.keyword(keywords...)
+		if err != nil {
+			return s, err
+		}
+		return set(s, i), nil
+	})
+}
+
+func alignProperty(set func(goui.Styles, goui.Align) goui.Styles) property {
+	keywords := []string{"auto", "flex-start", "start", "center", "flex-end", "end", "stretch", "baseline", "space-between", "space-around"}
+	aligns := []goui.Align{goui.AlignAuto, goui.AlignFlexStart, goui.AlignFlexStart, goui.AlignCenter, goui.AlignFlexEnd, goui.AlignFlexEnd,
+		goui.AlignStretch, goui.AlignBaseline, goui.AlignSpaceBetween, goui.AlignSpaceAround}
+	return keywordProperty(keywords, func(s goui.Styles, i int) goui.Styles { return set(s, aligns[i]) })
+}
+
+// readEdge reads the value of one edge of a property like margin or border-width
+type readEdge func(v *values, s goui.Styles, edge goui.Edge) (goui.Styles, error)
+
+// sideEdges are the edges that 1 to 4 values apply to, like in the css margin shorthand
+var sideEdges = [][]goui.Edge{
+	1: {goui.EdgeAll},
+	2: {goui.EdgeVertical, goui.EdgeHorizontal},
+	3: {goui.EdgeTop, goui.EdgeHorizontal, goui.EdgeBottom},
+	4: {goui.EdgeTop, goui.EdgeRight, goui.EdgeBottom, goui.EdgeLeft},
+}
+
+// edgesProperty returns a property with 1 to 4 values for the edges
+func edgesProperty(read readEdge) property {
+	return func(v *values) (goui.Styles, error) {
+		count, err := countValues(v, func(v *values, s goui.Styles) (goui.Styles, error) { return read(v, s, goui.EdgeAll) })
+		if err != nil {
+			return goui.Styles{}, err
+		}
+		s := goui.NewStyles()
+		for _, edge := range sideEdges[count] {
+			s, _ = read(v, s, edge)
+		}
+		return s, nil
+	}
+}
+
+func edgeProperty(edge goui.Edge, read readEdge) property {
+	return single(func(v *values, s goui.Styles) (goui.Styles, error) { return read(v, s, edge) })
+}
+
+// sideCorners are the corners that 1 to 4 values apply to, like in the css border-radius shorthand
+var sideCorners = [][][]goui.Corner{
+	1: {{goui.CornerAll}},
+	2: {{goui.CornerTopLeft, goui.CornerBottomRight}, {goui.CornerTopRight, goui.CornerBottomLeft}},
+	3: {{goui.CornerTopLeft}, {goui.CornerTopRight, goui.CornerBottomLeft}, {goui.CornerBottomRight}},
+	4: {{goui.CornerTopLeft}, {goui.CornerTopRight}, {goui.CornerBottomRight}, {goui.CornerBottomLeft}},
+}
+
+func cornersProperty(read func(v *values, s goui.Styles, corner goui.Corner) (goui.Styles, error)) property {
+	return func(v *values) (goui.Styles, error) {
+		count, err := countValues(v, func(v *values, s goui.Styles) (goui.Styles, error) { return read(v, s, goui.CornerAll) })
+		if err != nil {
+			return goui.Styles{}, err
+		}
+		s := goui.NewStyles()
+		for _, corners := range sideCorners[count] {
+			start := v.pos
+			for _, corner := range corners {
+				v.pos = start
+				s, _ = read(v, s, corner)
+			}
+		}
+		return s, nil
+	}
+}
+
+// countValues checks that there are 1 to 4 valid values and rewinds to the first one
+func countValues(v *values, read func(v *values, s goui.Styles) (goui.Styles, error)) (int, error) {
+	count := 0
+	for s := goui.NewStyles(); !v.done(); count++ {
+		var err error
+		if s, err = read(v, s); err != nil {
+			return 0, err
+		}
+	}
+	if count > 4 {
+		return 0, errorAt(v.property, "expected 1 to 4 values, found %v", count)
+	}
+	v.pos = 0
+	return count, nil
+}
+
+func readMargin(v *values, s goui.Styles, edge goui.Edge) (goui.Styles, error) {
+	px, err := v.length()
+	return s.Margin(edge, px), err
+}
+
+func readPadding(v *values, s goui.Styles, edge goui.Edge) (goui.Styles, error) {
+	px, err := v.length()
+	return s.Padding(edge, px), err
+}
+
+func readBorderWidth(v *values, s goui.Styles, edge goui.Edge) (goui.Styles, error) {
+	px, err := v.length()
+	return s.BorderWidth(edge, px), err
+}
+
+func readBorderColor(v *values, s goui.Styles, edge goui.Edge) (goui.Styles, error) {
+	c, err := v.color()
+	return s.BorderColor(edge, c), err
+}
+
+var borderStyles = []string{"solid", "dashed", "dotted"}
+
+func readBorderStyle(v *values, s goui.Styles, edge goui.Edge) (goui.Styles, error) {
+	i, err := v.keyword(borderStyles...)
+	return s.BorderStyle(edge, []goui.BorderStyle{goui.BorderSolid, goui.BorderDashed, goui.BorderDotted}[i]), err
+}
+
+// readBorder reads the border shorthand, a width, style and color in any order, or none
+func readBorder(edge goui.Edge) property {
+	return func(v *values) (goui.Styles, error) {
+		s := goui.NewStyles()
+		if v.only("none") {
+			return s.BorderWidth(edge, 0), nil
+		}
+		for !v.done() {
+			var err error
+			switch {
+			case v.isLength():
+				s, err = readBorderWidth(v, s, edge)
+			case v.peek().kind == tokenIdent && !v.isColor():
+				s, err = readBorderStyle(v, s, edge)
+			default:
+				s, err = readBorderColor(v, s, edge)
+			}
+			if err != nil {
+				return s, err
+			}
+		}
+		return s, nil
+	}
+}
+
+// readFlex reads the flex shorthand, none, auto or the grow and shrink factors
+func readFlex(v *values) (goui.Styles, error) {
+	s := goui.NewStyles()
+	switch {
+	case v.only("none"):
+		return s.FlexGrow(0).FlexShrink(0), nil
+	case v.only("auto"):
+		return s.FlexGrow(1).FlexShrink(1), nil
+	}
+	grow, err := v.number()
+	if err != nil {
+		return s, err
+	}
+	s = s.FlexGrow(grow)
+	if v.peek().kind == tokenNumber && v.peek().unit == "" {
+		shrink, _ := v.number()
+		s = s.FlexShrink(shrink)
+	}
+	if !v.done() {
+		return s, errorAt(v.peek(), "flex-basis is not supported, set the width or height instead")
+	}
+	return s, nil
+}
+
+// readBackground reads a color and an image or gradient, in any order, or none
+func readBackground(v *values) (goui.Styles, error) {
+	s := goui.NewStyles()
+	if v.only("none") {
+		return s.Background(namedColors["transparent"]).NoBackgroundPaint(), nil
+	}
+	for !v.done() {
+		var err error
+		if v.isColor() {
+			var c color.Color
+			c, err = v.color()
+			s = s.Background(c)
+		} else {
+			s, err = readImage(v, s)
+		}
+		if err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
+
+func readBackgroundImage(v *values) (goui.Styles, error) {
+	s := goui.NewStyles()
+	if v.only("none") {
+		return s.NoBackgroundPaint(), nil
+	}
+	s, err := readImage(v, s)
+	if err != nil {
+		return s, err
+	}
+	return s, v.end()
+}
+
+// readImage reads a gradient function or url()
+func readImage(v *values, s goui.Styles) (goui.Styles, error) {
+	t := v.peek()
+	if t.is(tokenFunction, "url") {
+		path, err := v.url()
+		return s.BackgroundImage(path), err
+	}
+	if t.kind != tokenFunction {
+		return s, v.missing("a color, gradient or url()")
+	}
+
+	name, args, err := v.function()
+	if err != nil {
+		return s, err
+	}
+	items := args.list()
+	switch name {
+	case "linear-gradient":
+		angle := 180.0
+		if items[0].isAngle() {
+			if angle, err = items[0].angle(); err != nil {
+				return s, err
+			}
+			if err := items[0].end(); err != nil {
+				return s, err
+			}
+			items = items[1:]
+		} else if items[0].isKeyword("to") {
+			if angle, err = readSide(items[0]); err != nil {
+				return s, err
+			}
+			items = items[1:]
+		}
+		stops, err := readStops(args, items)
+		return s.LinearGradient(angle, stops...), err
+
+	case "radial-gradient":
+		stops, err := readStops(args, items)
+		return s.RadialGradient(stops...), err
+
+	case "box-gradient":
+		if len(items) != 3 {
+			return s, errorAt(t, "box-gradient needs a feather length, an inner and an outer color")
+		}
+		feather, err := items[0].length()
+		if err != nil {
+			return s, err
+		}
+		inner, err := items[1].color()
+		if err != nil {
+			return s, err
+		}
+		outer, err := items[2].color()
+		if err != nil {
+			return s, err
+		}
+		for _, item := range items {
+			if err := item.end(); err != nil {
+				return s, err
+			}
+		}
+		return s.BoxGradient(feather, inner, outer), nil
+	}
+	return s, errorAt(t, "unsupported function %v, expected linear-gradient, radial-gradient, box-gradient or url", t)
+}
+
+// readSide reads the direction of a linear gradient like "to top right" as an angle
+func readSide(v *values) (float64, error) {
+	start := v.next()
+	var x, y float64
+	for !v.done() {
+		i, err := v.keyword("top", "right", "bottom", "left")
+		if err != nil {
+			return 0, err
+		}
+		switch i {
+		case 0:
+			y = -1
+		case 1:
+			x = 1
+		case 2:
+			y = 1
+		case 3:
+			x = -1
+		}
+	}
+	switch {
+	case x == 0 && y == 0:
+		return 0, errorAt(start, "missing side after to")
+	case x == 0:
+		return 90 + 90*y, nil
+	case y == 0:
+		return 180 - 90*x, nil
+	}
+	// the corners are at 45 degrees, not towards the corner of the box like in css
+	return 180 - 90*x + 45*x*y, nil
+}
+
+// readStops reads color stops with optional offsets in percent, the missing offsets are spread evenly
+// between the ones that are set, like in css
+func readStops(args *values, items []*values) ([]goui.GradientStop, error) {
+	if len(items) < 2 {
+		return nil, errorAt(args.property, "a gradient needs at least 2 colors")
+	}
+	stops := make([]goui.GradientStop, len(items))
+	known := make([]bool, len(items))
+	for i, item := range items {
+		c, err := item.color()
+		if err != nil {
+			return nil, err
+		}
+		stops[i].Color = c
+		if !item.done() {
+			pct, err := item.percentage()
+			if err != nil {
+				return nil, err
+			}
+			stops[i].Offset, known[i] = pct/100, true
+		}
+		if err := item.end(); err != nil {
+			return nil, err
+		}
+	}
+
+	last := len(stops) - 1
+	if !known[0] {
+		stops[0].Offset, known[0] = 0, true
+	}
+	if !known[last] {
+		stops[last].Offset, known[last] = 1, true
+	}
+	for i := 1; i <= last; i++ {
+		// an offset can't be before the one of the previous stop
+		if known[i] && stops[i].Offset < stops[i-1].Offset {
+			stops[i].Offset = stops[i-1].Offset
+		}
+	}
+	for start := 0; start < last; {
+		end := start + 1
+		for !known[end] {
+			end++
+		}
+		for i := start + 1; i < end; i++ {
+			stops[i].Offset = stops[start].Offset + (stops[end].Offset-stops[start].Offset)*float64(i-start)/float64(end-start)
+		}
+		start = end
+	}
+	return stops, nil
+}
+
+// readBoxShadow reads a list of shadows, each with an optional inset, 2 to 4 lengths and a color, or none
+func readBoxShadow(v *values) (goui.Styles, error) {
+	s := goui.NewStyles()
+	if v.only("none") {
+		return s.NoBoxShadow(), nil
+	}
+	for _, item := range v.list() {
+		var lengths []float64
+		var c color.Color = namedColors["black"]
+		inset := false
+		for !item.done() {
+			switch {
+			case item.isKeyword("inset"):
+				item.next()
+				inset = true
+			case item.isLength():
+				px, _ := item.length()
+				lengths = append(lengths, px)
+			default:
+				var err error
+				if c, err = item.color(); err != nil {
+					return s, err
+				}
+			}
+		}
+		if len(lengths) < 2 || len(lengths) > 4 {
+			return s, errorAt(item.property, "a shadow needs 2 to 4 lengths, found %v", len(lengths))
+		}
+		lengths = append(lengths, 0, 0)
+		if inset {
+			s = s.InsetBoxShadow(lengths[0], lengths[1], lengths[2], lengths[3], c)
+		} else {
+			s = s.BoxShadow(lengths[0], lengths[1], lengths[2], lengths[3], c)
+		}
+	}
+	return s, nil
+}
+
+// readTransform reads transform functions. Since goui always translates, then rotates and then scales,
+// the translations, rotations and scales are each combined no matter in which order they are written.
+func readTransform(v *values) (goui.Styles, error) {
+	s := goui.NewStyles()
+	if v.only("none") {
+		return s.Translate(0, 0).Rotate(0).Scale(1, 1), nil
+	}
+
+	var tx, ty, degrees float64
+	sx, sy := 1.0, 1.0
+	var translated, rotated, scaled bool
+	for !v.done() {
+		t := v.peek()
+		name, args, err := v.function()
+		if err != nil {
+			return s, err
+		}
+		items := args.list()
+		switch name {
+		case "translate", "translatex", "translatey":
+			var x, y float64
+			if x, y, err = readPair(t, items, (*values).length, 0); err != nil {
+				return s, err
+			}
+			if name == "translatey" {
+				x, y = 0, x
+			}
+			if name != "translate" && len(items) != 1 {
+				return s, errorAt(t, "%v needs one length", t)
+			}
+			tx, ty, translated = tx+x, ty+y, true
+		case "scale", "scalex", "scaley":
+			var x, y float64
+			if x, y, err = readPair(t, items, (*values).number, -1); err != nil {
+				return s, err
+			}
+			switch name {
+			case "scalex":
+				y = 1
+			case "scaley":
+				x, y = 1, x
+			}
+			if name != "scale" && len(items) != 1 {
+				return s, errorAt(t, "%v needs one number", t)
+			}
+			sx, sy, scaled = sx*x, sy*y, true
+		case "rotate":
+			if len(items) != 1 {
+				return s, errorAt(t, "rotate needs one angle")
+			}
+			angle, err := items[0].angle()
+			if err != nil {
+				return s, err
+			}
+			if err := items[0].end(); err != nil {
+				return s, err
+			}
+			degrees, rotated = degrees+angle, true
+		default:
+			return s, errorAt(t, "unsupported transform %v, expected translate, scale or rotate", t)
+		}
+	}
+	if translated {
+		s = s.Translate(tx, ty)
+	}
+	if rotated {
+		s = s.Rotate(degrees)
+	}
+	if scaled {
+		s = s.Scale(sx, sy)
+	}
+	return s, nil
+}
+
+// readPair reads one or two comma separated arguments, the second defaults to missing, or to the first
+// when missing is -1
+func readPair(fn token, items []*values, read func(*values) (float64, error), missing float64) (float64, float64, error) {
+	if len(items) > 2 {
+		return 0, 0, errorAt(fn, "%v needs 1 or 2 arguments", fn)
+	}
+	var pair [2]float64
+	for i, item := range items {
+		n, err := read(item)
+		if err != nil {
+			return 0, 0, err
+		}
+		if err := item.end(); err != nil {
+			return 0, 0, err
+		}
+		pair[i] = n
+	}
+	if len(items) == 1 {
+		pair[1] = missing
+		if missing == -1 {
+			pair[1] = pair[0]
+		}
+	}
+	return pair[0], pair[1], nil
+}
+
+// readTranslate reads the translate property, an x and an optional y length separated by spaces
+func readTranslate(v *values) (goui.Styles, error) {
+	s := goui.NewStyles()
+	if v.only("none") {
+		return s.Translate(0, 0), nil
+	}
+	x, err := v.length()
+	if err != nil {
+		return s, err
+	}
+	var y float64
+	if !v.done() {
+		if y, err = v.length(); err != nil {
+			return s, err
+		}
+	}
+	return s.Translate(x, y), v.end()
+}
+
+// readScale reads the scale property, an x and an optional y factor separated by spaces
+func readScale(v *values) (goui.Styles, error) {
+	s := goui.NewStyles()
+	if v.only("none") {
+		return s.Scale(1, 1), nil
+	}
+	x, err := v.number()
+	if err != nil {
+		return s, err
+	}
+	y := x
+	if !v.done() {
+		if y, err = v.number(); err != nil {
+			return s, err
+		}
+	}
+	return s.Scale(x, y), v.end()
+}
+
+// readTransformOrigin reads percentages or the keywords left, center, right, top and bottom
+func readTransformOrigin(v *values) (goui.Styles, error) {
+	origin := []float64{50, 50}
+	horizontal := map[string]float64{"left": 0, "right": 100}
+	vertical := map[string]float64{"top": 0, "bottom": 100}
+	for i := 0; i < 2 && !v.done(); i++ {
+		t := v.peek()
+		if t.kind == tokenIdent {
+			keyword := strings.ToLower(t.text)
+			if pct, ok := horizontal[keyword]; ok {
+				origin[0] = pct
+			} else if pct, ok := vertical[keyword]; ok {
+				origin[1] = pct
+			} else if keyword != "center" {
+				return goui.Styles{}, errorAt(t, "expected left, center, right, top or bottom, found %v", t)
+			}
+			v.next()
+			continue
+		}
+		pct, err := v.percentage()
+		if err != nil {
+			return goui.Styles{}, err
+		}
+		origin[i] = pct
+	}
+	return goui.NewStyles().TransformOrigin(origin[0], origin[1]), v.end()
+}
+
+// transitionProperties maps the css names of the properties that can be animated to goui properties
+var transitionProperties = map[string]goui.Property{"background-color": goui.PropertyBackground}
+
+func init() {
+	for p := goui.PropertyAll; p.String() != "unknown"; p++ {
+		transitionProperties[p.String()] = p
+	}
+}
+
+// readTransition reads a list of transitions, each with a property, a duration and an optional easing
+func readTransition(v *values) (goui.Styles, error) {
+	s := goui.NewStyles()
+	for _, item := range v.list() {
+		prop := goui.PropertyAll
+		var duration time.Duration
+		easing := goui.Ease
+		hasDuration := false
+		for !item.done() {
+			t := item.peek()
+			switch {
+			case item.isDuration():
+				if hasDuration {
+					return s, errorAt(t, "transition delays are not supported")
+				}
+				duration, _ = item.duration()
+				hasDuration = true
+			case item.isEasing():
+				easing, _ = item.easing()
+			case t.kind == tokenIdent:
+				p, ok := transitionProperties[strings.ToLower(t.text)]
+				if !ok {
+					return s, errorAt(t, "%v can't be animated", t.text)
+				}
+				prop = p
+				item.next()
+			default:
+				return s, errorAt(t, "expected a property, duration or easing, found %v", t)
+			}
+		}
+		if !hasDuration {
+			return s, errorAt(item.property, "missing duration")
+		}
+		s = s.Transition(prop, duration, easing)
+	}
+	return s, nil
+}
+
+// readOutline reads the width and color of the focus ring, the style must be solid, or none
+func readOutline(v *values) (goui.Styles, error) {
+	s := goui.NewStyles()
+	if v.only("none") {
+		return s.FocusRing(0, nil), nil
+	}
+	width := -1.0
+	var c color.Color
+	for !v.done() {
+		var err error
+		switch {
+		case v.isLength():
+			width, err = v.length()
+		case v.isColor():
+			c, err = v.color()
+		default:
+			_, err = v.keyword("solid")
+		}
+		if err != nil {
+			return s, err
+		}
+	}
+	return s.FocusRing(width, c), nil
+}
@@ -0,0 +1,239 @@
+package css
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenFunction // an identifier followed by an opening parenthesis, like rgb(
+	tokenNumber   // a number with an optional unit like px or %
+	tokenHash     // # followed by a name, like a hex color
+	tokenString   // quoted text, or the unquoted argument of url()
+	tokenDelim    // any other character
+)
+
+type token struct {
+	kind tokenKind
+	text string // the identifier, function name, string, hash name or delimiter
+	num  float64
+	unit string
+	line int
+	col  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of file"
+	case tokenFunction:
+		return t.text + "("
+	case tokenNumber:
+		return strconv.FormatFloat(t.num, 'f', -1, 64) + t.unit
+	case tokenHash:
+		return "#" + t.text
+	case tokenString:
+		return strconv.Quote(t.text)
+	}
+	return t.text
+}
+
+func (t token) is(kind tokenKind, text string) bool {
+	return t.kind == kind && t.text == text
+}
+
+func (t token) isDelim(text string) bool {
+	return t.is(tokenDelim, text)
+}
+
+// scanner splits css into tokens, skipping white space and comments
+type scanner struct {
+	src       []rune
+	pos       int
+	line, col int
+	tokens    []token
+	errors    *errorList
+}
+
+// tokenize returns the tokens of the css, ending with an EOF token
+func tokenize(src string, errors *errorList) []token {
+	s := &scanner{src: []rune(src), line: 1, col: 1, errors: errors}
+	for s.scan() {
+	}
+	return s.tokens
+}
+
+func (s *scanner) peekRune(offset int) rune {
+	if s.pos+offset >= len(s.src) {
+		return 0
+	}
+	return s.src[s.pos+offset]
+}
+
+func (s *scanner) readRune() rune {
+	r := s.src[s.pos]
+	s.pos++
+	if r == '\n' {
+		s.line++
+		s.col = 1
+	} else {
+		s.col++
+	}
+	return r
+}
+
+func (s *scanner) skipSpaceAndComments() {
+	for s.pos < len(s.src) {
+		if unicode.IsSpace(s.peekRune(0)) {
+			s.readRune()
+		} else if s.peekRune(0) == '/' && s.peekRune(1) == '*' {
+			line, col := s.line, s.col
+			s.readRune()
+			s.readRune()
+			for s.pos < len(s.src) && !(s.peekRune(0) == '*' && s.peekRune(1) == '/') {
+				s.readRune()
+			}
+			if s.pos >= len(s.src) {
+				s.errors.add(line, col, "comment is not closed")
+				return
+			}
+			s.readRune()
+			s.readRune()
+		} else {
+			return
+		}
+	}
+}
+
+func isNameRune(r rune) bool {
+	return r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isNameStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func (s *scanner) startsIdent() bool {
+	r := s.peekRune(0)
+	if r == '-' {
+		return isNameStart(s.peekRune(1)) || s.peekRune(1) == '-'
+	}
+	return isNameStart(r)
+}
+
+func (s *scanner) startsNumber() bool {
+	r := s.peekRune(0)
+	if r == '-' || r == '+' {
+		r = s.peekRune(1)
+		if r == '.' {
+			return unicode.IsDigit(s.peekRune(2))
+		}
+		return unicode.IsDigit(r)
+	}
+	if r == '.' {
+		return unicode.IsDigit(s.peekRune(1))
+	}
+	return unicode.IsDigit(r)
+}
+
+func (s *scanner) readName() string {
+	var b strings.Builder
+	for s.pos < len(s.src) && isNameRune(s.peekRune(0)) {
+		b.WriteRune(s.readRune())
+	}
+	return b.String()
+}
+
+// scan adds the next token, it returns false after adding the EOF token
+func (s *scanner) scan() bool {
+	s.skipSpaceAndComments()
+	t := token{line: s.line, col: s.col}
+	if s.pos >= len(s.src) {
+		t.kind = tokenEOF
+		s.tokens = append(s.tokens, t)
+		return false
+	}
+
+	switch r := s.peekRune(0); {
+	case s.startsNumber():
+		var b strings.Builder
+		b.WriteRune(s.readRune())
+		for unicode.IsDigit(s.peekRune(0)) || (s.peekRune(0) == '.' && unicode.IsDigit(s.peekRune(1))) {
+			b.WriteRune(s.readRune())
+		}
+		t.kind = tokenNumber
+		t.num, _ = strconv.ParseFloat(b.String(), 64)
+		if s.peekRune(0) == '%' {
+			s.readRune()
+			t.unit = "%"
+		} else if isNameStart(s.peekRune(0)) {
+			t.unit = strings.ToLower(s.readName())
+		}
+
+	case s.startsIdent():
+		t.kind = tokenIdent
+		t.text = s.readName()
+		if s.peekRune(0) == '(' {
+			s.readRune()
+			t.kind = tokenFunction
+			t.text = strings.ToLower(t.text)
+			if t.text == "url" {
+				s.tokens = append(s.tokens, t)
+				s.readURL()
+				return true
+			}
+		}
+
+	case r == '#':
+		s.readRune()
+		t.kind = tokenHash
+		t.text = s.readName()
+
+	case r == '"' || r == '\'':
+		s.readRune()
+		var b strings.Builder
+		for {
+			if s.pos >= len(s.src) || s.peekRune(0) == '\n' {
+				s.errors.add(t.line, t.col, "string is not closed")
+				break
+			}
+			c := s.readRune()
+			if c == r {
+				break
+			}
+			if c == '\\' && s.pos < len(s.src) {
+				c = s.readRune()
+			}
+			b.WriteRune(c)
+		}
+		t.kind = tokenString
+		t.text = b.String()
+
+	default:
+		t.kind = tokenDelim
+		t.text = string(s.readRune())
+	}
+	s.tokens = append(s.tokens, t)
+	return true
+}
+
+// readURL reads the argument of url() when it is not quoted, the closing parenthesis is left for the next token
+func (s *scanner) readURL() {
+	s.skipSpaceAndComments()
+	if r := s.peekRune(0); r == '"' || r == '\'' {
+		return
+	}
+
+	t := token{kind: tokenString, line: s.line, col: s.col}
+	var b strings.Builder
+	for s.pos < len(s.src) && s.peekRune(0) != ')' && !unicode.IsSpace(s.peekRune(0)) {
+		b.WriteRune(s.readRune())
+	}
+	t.text = b.String()
+	s.tokens = append(s.tokens, t)
+}
@@ -0,0 +1,539 @@
+package css
+
+import (
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"../../goui"
+)
+
+// values reads the tokens of a property value
+type values struct {
+	tokens   []token
+	pos      int
+	property token // errors at the end of the value are reported here
+}
+
+func (v *values) done() bool {
+	return v.pos >= len(v.tokens)
+}
+
+func (v *values) peek() token {
+	if v.done() {
+		return token{kind: tokenEOF, line: v.property.line, col: v.property.col}
+	}
+	return v.tokens[v.pos]
+}
+
+func (v *values) next() token {
+	t := v.peek()
+	if !v.done() {
+		v.pos++
+	}
+	return t
+}
+
+// end returns an error if there are tokens left
+func (v *values) end() error {
+	if !v.done() {
+		return errorAt(v.peek(), "unexpected %v", v.peek())
+	}
+	return nil
+}
+
+func (v *values) missing(what string) error {
+	t := v.peek()
+	if t.kind == tokenEOF {
+		return errorAt(t, "missing %v", what)
+	}
+	return errorAt(t, "expected %v, found %v", what, t)
+}
+
+// isKeyword returns if the next token is the identifier, ignoring case
+func (v *values) isKeyword(keyword string) bool {
+	t := v.peek()
+	return t.kind == tokenIdent && strings.EqualFold(t.text, keyword)
+}
+
+// only returns if the value is just the keyword
+func (v *values) only(keyword string) bool {
+	return len(v.tokens) == 1 && v.isKeyword(keyword)
+}
+
+// list splits the value at the commas that are not inside a function
+func (v *values) list() []*values {
+	var items []*values
+	start, depth := v.pos, 0
+	for i := v.pos; i < len(v.tokens); i++ {
+		t := v.tokens[i]
+		if t.kind == tokenFunction || t.isDelim("(") {
+			depth++
+		} else if t.isDelim(")") {
+			depth--
+		} else if depth == 0 && t.isDelim(",") {
+			items = append(items, &values{tokens: v.tokens[start:i], property: t})
+			start = i + 1
+		}
+	}
+	last := v.property
+	if len(v.tokens) > 0 {
+		last = v.tokens[len(v.tokens)-1]
+	}
+	items = append(items, &values{tokens: v.tokens[start:], property: last})
+	v.pos = len(v.tokens)
+	return items
+}
+
+// function reads a function and returns its name and arguments
+func (v *values) function() (string, *values, error) {
+	t := v.next()
+	if t.kind != tokenFunction {
+		return "", nil, errorAt(t, "expected a function, found %v", t)
+	}
+	end := closingParen(v.tokens, v.pos-1)
+	if end < 0 {
+		return "", nil, errorAt(t, "%v is not closed", t)
+	}
+	args := &values{tokens: v.tokens[v.pos:end], property: v.tokens[end]}
+	v.pos = end + 1
+	return t.text, args, nil
+}
+
+// length reads a length in px, a 0 doesn't need a unit
+func (v *values) length() (float64, error) {
+	t := v.peek()
+	if t.kind != tokenNumber || (t.unit != "px" && !(t.unit == "" && t.num == 0)) {
+		return 0, v.missing("a length in px")
+	}
+	v.next()
+	return t.num, nil
+}
+
+// isLength returns if the next token is a length
+func (v *values) isLength() bool {
+	t := v.peek()
+	return t.kind == tokenNumber && (t.unit == "px" || (t.unit == "" && t.num == 0))
+}
+
+// size reads a length in px or a percentage, it returns if it was a percentage
+func (v *values) size() (float64, bool, error) {
+	t := v.peek()
+	if t.kind == tokenNumber && t.unit == "%" {
+		v.next()
+		return t.num, true, nil
+	}
+	if !v.isLength() {
+		return 0, false, v.missing("a length in px or %")
+	}
+	px, err := v.length()
+	return px, false, err
+}
+
+func (v *values) percentage() (float64, error) {
+	t := v.peek()
+	if t.kind != tokenNumber || t.unit != "%" {
+		return 0, v.missing("a percentage")
+	}
+	v.next()
+	return t.num, nil
+}
+
+// number reads a number without a unit
+func (v *values) number() (float64, error) {
+	t := v.peek()
+	if t.kind != tokenNumber || t.unit != "" {
+		return 0, v.missing("a number")
+	}
+	v.next()
+	return t.num, nil
+}
+
+// angle reads an angle in deg, rad, grad or turn and returns it in degrees
+func (v *values) angle() (float64, error) {
+	t := v.peek()
+	if t.kind == tokenNumber {
+		switch t.unit {
+		case "deg":
+			v.next()
+			return t.num, nil
+		case "rad":
+			v.next()
+			return t.num * 180 / math.Pi, nil
+		case "grad":
+			v.next()
+			return t.num * 0.9, nil
+		case "turn":
+			v.next()
+			return t.num * 360, nil
+		case "":
+			if t.num == 0 {
+				v.next()
+				return 0, nil
+			}
+		}
+	}
+	return 0, v.missing("an angle like 45deg")
+}
+
+func (v *values) isAngle() bool {
+	t := v.peek()
+	switch t.unit {
+	case "deg", "rad", "grad", "turn":
+		return t.kind == tokenNumber
+	}
+	return false
+}
+
+// duration reads a time in s or ms
+func (v *values) duration() (time.Duration, error) {
+	t := v.peek()
+	if t.kind == tokenNumber {
+		switch t.unit {
+		case "s":
+			v.next()
+			return time.Duration(t.num * float64(time.Second)), nil
+		case "ms":
+			v.next()
+			return time.Duration(t.num * float64(time.Millisecond)), nil
+		}
+	}
+	return 0, v.missing("a duration like 150ms")
+}
+
+func (v *values) isDuration() bool {
+	t := v.peek()
+	return t.kind == tokenNumber && (t.unit == "s" || t.unit == "ms")
+}
+
+var easings = map[string]goui.Easing{
+	"linear":      goui.Linear,
+	"ease":        goui.Ease,
+	"ease-in":     goui.EaseIn,
+	"ease-out":    goui.EaseOut,
+	"ease-in-out": goui.EaseInOut,
+}
+
+// easing reads an easing keyword or a cubic-bezier function
+func (v *values) easing() (goui.Easing, error) {
+	t := v.peek()
+	if t.kind == tokenIdent {
+		if easing, ok := easings[strings.ToLower(t.text)]; ok {
+			v.next()
+			return easing, nil
+		}
+	}
+	if t.is(tokenFunction, "cubic-bezier") {
+		_, args, err := v.function()
+		if err != nil {
+			return nil, err
+		}
+		numbers, err := args.numbers(4)
+		if err != nil {
+			return nil, err
+		}
+		return goui.CubicBezier(numbers[0], numbers[1], numbers[2], numbers[3]), nil
+	}
+	return nil, v.missing("an easing like ease-out")
+}
+
+func (v *values) isEasing() bool {
+	t := v.peek()
+	_, ok := easings[strings.ToLower(t.text)]
+	return (t.kind == tokenIdent && ok) || t.is(tokenFunction, "cubic-bezier")
+}
+
+// numbers reads n comma separated numbers, the arguments of a function
+func (v *values) numbers(n int) ([]float64, error) {
+	items := v.list()
+	if len(items) != n {
+		return nil, errorAt(v.property, "expected %v numbers, found %v", n, len(items))
+	}
+	numbers := make([]float64, n)
+	for i, item := range items {
+		number, err := item.number()
+		if err != nil {
+			return nil, err
+		}
+		if err := item.end(); err != nil {
+			return nil, err
+		}
+		numbers[i] = number
+	}
+	return numbers, nil
+}
+
+// keyword reads one of the identifiers and returns its index
+func (v *values) keyword(keywords ...string) (int, error) {
+	t := v.peek()
+	if t.kind == tokenIdent {
+		for i, k := range keywords {
+			if strings.EqualFold(t.text, k) {
+				v.next()
+				return i, nil
+			}
+		}
+	}
+	return 0, v.missing(strings.Join(keywords, ", "))
+}
+
+// str reads a quoted string or an identifier, like a font name
+func (v *values) str() (string, error) {
+	t := v.peek()
+	if t.kind != tokenString && t.kind != tokenIdent {
+		return "", v.missing("a name")
+	}
+	v.next()
+	return t.text, nil
+}
+
+// url reads url(path)
+func (v *values) url() (string, error) {
+	if !v.peek().is(tokenFunction, "url") {
+		return "", v.missing("url()")
+	}
+	_, args, err := v.function()
+	if err != nil {
+		return "", err
+	}
+	path, err := args.str()
+	if err != nil {
+		return "", err
+	}
+	return path, args.end()
+}
+
+func (v *values) isColor() bool {
+	t := v.peek()
+	switch t.kind {
+	case tokenHash:
+		return true
+	case tokenFunction:
+		switch t.text {
+		case "rgb", "rgba", "hsl", "hsla", "token":
+			return true
+		}
+	case tokenIdent:
+		_, ok := namedColors[strings.ToLower(t.text)]
+		return ok
+	}
+	return false
+}
+
+// color reads a hex color, a named color, an rgb, rgba, hsl or hsla function, or token() with the name
+// of a color of the theme
+func (v *values) color() (color.Color, error) {
+	t := v.peek()
+	switch t.kind {
+	case tokenHash:
+		v.next()
+		c, ok := parseHex(t.text)
+		if !ok {
+			return nil, errorAt(t, "invalid color %v, expected #rgb, #rgba, #rrggbb or #rrggbbaa", t)
+		}
+		return c, nil
+
+	case tokenIdent:
+		if c, ok := namedColors[strings.ToLower(t.text)]; ok {
+			v.next()
+			return c, nil
+		}
+		return nil, errorAt(t, "unknown color %v", t.text)
+
+	case tokenFunction:
+		switch t.text {
+		case "rgb", "rgba":
+			_, args, err := v.function()
+			if err != nil {
+				return nil, err
+			}
+			return args.rgb()
+		case "hsl", "hsla":
+			_, args, err := v.function()
+			if err != nil {
+				return nil, err
+			}
+			return args.hsl()
+		case "token":
+			_, args, err := v.function()
+			if err != nil {
+				return nil, err
+			}
+			name, err := args.str()
+			if err != nil {
+				return nil, err
+			}
+			return goui.Token(name), args.end()
+		}
+	}
+	return nil, v.missing("a color")
+}
+
+// colorArgs reads 3 or 4 arguments separated by commas or spaces, the alpha can also come after a slash
+func (v *values) colorArgs() ([]token, error) {
+	var args []token
+	for !v.done() {
+		t := v.next()
+		if t.isDelim(",") || t.isDelim("/") {
+			continue
+		}
+		if t.kind != tokenNumber {
+			return nil, errorAt(t, "expected a number, found %v", t)
+		}
+		args = append(args, t)
+	}
+	if len(args) != 3 && len(args) != 4 {
+		return nil, errorAt(v.property, "expected 3 or 4 color components, found %v", len(args))
+	}
+	return args, nil
+}
+
+// alpha returns the 4th color component as a number from 0 to 1
+func alpha(args []token) float64 {
+	if len(args) < 4 {
+		return 1
+	}
+	if args[3].unit == "%" {
+		return clamp(args[3].num / 100)
+	}
+	return clamp(args[3].num)
+}
+
+func (v *values) rgb() (color.Color, error) {
+	args, err := v.colorArgs()
+	if err != nil {
+		return nil, err
+	}
+	var rgb [3]float64
+	for i := range rgb {
+		if args[i].unit == "%" {
+			rgb[i] = clamp(args[i].num / 100)
+		} else {
+			rgb[i] = clamp(args[i].num / 255)
+		}
+	}
+	return rgba(rgb[0], rgb[1], rgb[2], alpha(args)), nil
+}
+
+func (v *values) hsl() (color.Color, error) {
+	args, err := v.colorArgs()
+	if err != nil {
+		return nil, err
+	}
+	h := math.Mod(args[0].num, 360) / 360
+	if h < 0 {
+		h++
+	}
+	s, l := clamp(args[1].num/100), clamp(args[2].num/100)
+
+	// from https://www.w3.org/TR/css-color-3/#hsl-color
+	m2 := l + s - l*s
+	if l <= 0.5 {
+		m2 = l * (s + 1)
+	}
+	m1 := l*2 - m2
+	hue := func(h float64) float64 {
+		if h < 0 {
+			h++
+		} else if h > 1 {
+			h--
+		}
+		switch {
+		case h*6 < 1:
+			return m1 + (m2-m1)*h*6
+		case h*2 < 1:
+			return m2
+		case h*3 < 2:
+			return m1 + (m2-m1)*(2.0/3-h)*6
+		}
+		return m1
+	}
+	return rgba(hue(h+1.0/3), hue(h), hue(h-1.0/3), alpha(args)), nil
+}
+
+func clamp(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+func rgba(r, g, b, a float64) color.Color {
+	return color.NRGBA{uint8(math.Round(r * 255)), uint8(math.Round(g * 255)), uint8(math.Round(b * 255)), uint8(math.Round(a * 255))}
+}
+
+func parseHex(hex string) (color.Color, bool) {
+	if len(hex) == 3 || len(hex) == 4 {
+		long := make([]byte, 0, 8)
+		for i := 0; i < len(hex); i++ {
+			long = append(long, hex[i], hex[i])
+		}
+		hex = string(long)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return nil, false
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, false
+	}
+	return color.NRGBA{uint8(n >> 24), uint8(n >> 16), uint8(n >> 8), uint8(n)}, true
+}
+
+// namedColors are the basic css colors and a few of the extended ones that are used most
+var namedColors = map[string]color.Color{
+	"transparent": color.NRGBA{0, 0, 0, 0},
+	"black":       color.NRGBA{0, 0, 0, 255},
+	"silver":      color.NRGBA{192, 192, 192, 255},
+	"gray":        color.NRGBA{128, 128, 128, 255},
+	"grey":        color.NRGBA{128, 128, 128, 255},
+	"white":       color.NRGBA{255, 255, 255, 255},
+	"maroon":      color.NRGBA{128, 0, 0, 255},
+	"red":         color.NRGBA{255, 0, 0, 255},
+	"purple":      color.NRGBA{128, 0, 128, 255},
+	"fuchsia":     color.NRGBA{255, 0, 255, 255},
+	"magenta":     color.NRGBA{255, 0, 255, 255},
+	"green":       color.NRGBA{0, 128, 0, 255},
+	"lime":        color.NRGBA{0, 255, 0, 255},
+	"olive":       color.NRGBA{128, 128, 0, 255},
+	"yellow":      color.NRGBA{255, 255, 0, 255},
+	"navy":        color.NRGBA{0, 0, 128, 255},
+	"blue":        color.NRGBA{0, 0, 255, 255},
+	"teal":        color.NRGBA{0, 128, 128, 255},
+	"aqua":        color.NRGBA{0, 255, 255, 255},
+	"cyan":        color.NRGBA{0, 255, 255, 255},
+	"orange":      color.NRGBA{255, 165, 0, 255},
+	"pink":        color.NRGBA{255, 192, 203, 255},
+	"brown":       color.NRGBA{165, 42, 42, 255},
+	"gold":        color.NRGBA{255, 215, 0, 255},
+	"indigo":      color.NRGBA{75, 0, 130, 255},
+	"violet":      color.NRGBA{238, 130, 238, 255},
+	"crimson":     color.NRGBA{220, 20, 60, 255},
+	"coral":       color.NRGBA{255, 127, 80, 255},
+	"salmon":      color.NRGBA{250, 128, 114, 255},
+	"tomato":      color.NRGBA{255, 99, 71, 255},
+	"orangered":   color.NRGBA{255, 69, 0, 255},
+	"steelblue":   color.NRGBA{70, 130, 180, 255},
+	"royalblue":   color.NRGBA{65, 105, 225, 255},
+	"dodgerblue":  color.NRGBA{30, 144, 255, 255},
+	"skyblue":     color.NRGBA{135, 206, 235, 255},
+	"lightblue":   color.NRGBA{173, 216, 230, 255},
+	"darkblue":    color.NRGBA{0, 0, 139, 255},
+	"lightgray":   color.NRGBA{211, 211, 211, 255},
+	"lightgrey":   color.NRGBA{211, 211, 211, 255},
+	"darkgray":    color.NRGBA{169, 169, 169, 255},
+	"darkgrey":    color.NRGBA{169, 169, 169, 255},
+	"dimgray":     color.NRGBA{105, 105, 105, 255},
+	"dimgrey":     color.NRGBA{105, 105, 105, 255},
+	"whitesmoke":  color.NRGBA{245, 245, 245, 255},
+	"gainsboro":   color.NRGBA{220, 220, 220, 255},
+	"darkgreen":   color.NRGBA{0, 100, 0, 255},
+	"seagreen":    color.NRGBA{46, 139, 87, 255},
+	"limegreen":   color.NRGBA{50, 205, 50, 255},
+	"darkred":     color.NRGBA{139, 0, 0, 255},
+	"firebrick":   color.NRGBA{178, 34, 34, 255},
+	"slategray":   color.NRGBA{112, 128, 144, 255},
+	"slategrey":   color.NRGBA{112, 128, 144, 255},
+}
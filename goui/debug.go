@@ -13,6 +13,8 @@ var (
 	errorMessages  = []string{}
 )
 
+// Debug turns on features for development, like reloading stylesheets, fonts and images when their
+// files change. Call it before Render.
 func Debug(on bool) {
 	debug = on
 }
@@ -40,6 +42,6 @@ func logError(msg ...interface{}) {
 }
 
 func addSpaces(msg ...interface{}) string {
-	str := fmt.Sprintln(msg)
+	str := fmt.Sprintln(msg...)
 	return strings.TrimSuffix(str, "\n")
 }
@@ -7,62 +7,167 @@ import (
 	"github.com/shibukawa/nanovgo"
 )
 
-func drawRect(ctx *nanovgo.Context, parentX, parentY float32, l *flex.Node, s Styles) {
+func drawRect(ctx canvas, parentX, parentY float32, l *flex.Node, s Styles) {
 	x := parentX + l.LayoutGetLeft()
 	y := parentY + l.LayoutGetTop()
 	w := l.LayoutGetWidth()
 	h := l.LayoutGetHeight()
-	_, _, _, _ = x, y, w, h
 
 	background := s.background
 	if background == nil {
-		background = defaultBackground
+		background = color.RGBA{}
 	}
 
+	r := radii(s, w, h)
+	drawShadows(ctx, x, y, w, h, r, s.boxShadows)
+
 	ctx.BeginPath()
 	ctx.SetFillColor(colorToNanoColor(background))
-	ctx.RoundedRect(x, y, w, h, float32(s.borderRadius))
+	roundedRect(ctx, x, y, w, h, r)
 	ctx.Fill()
+	drawBackgroundPaint(ctx, x, y, w, h, r, s.backgroundPaint)
+
+	paddingX, paddingY, paddingW, paddingH := paddingBox(parentX, parentY, l)
+	drawInsetShadows(ctx, paddingX, paddingY, paddingW, paddingH, paddingRadii(r, l), s.boxShadows)
+
+	drawBorder(ctx, x, y, w, h, s)
 }
 
-func drawText(ctx *nanovgo.Context, text string, parentX, parentY float32, l *flex.Node, s Styles) {
-	x := parentX + l.LayoutGetLeft()
-	y := parentY + l.LayoutGetTop()
+func drawText(ctx canvas, text string, parentX, parentY float32, l *flex.Node, s Styles) {
+	// text is drawn inside the content box
+	x, y, w, h := contentBox(parentX, parentY, l)
 
-	textAlign := nanovgo.Align(0)
+	layout := layoutText(ctx, text, s, w)
+	_, textHeight := layout.size()
+
+	textAlign := nanovgo.AlignTop
 
 	if s.textAlign == TextRight {
 		textAlign |= nanovgo.AlignRight
-		x += l.LayoutGetWidth()
+		x += w
 	} else if s.textAlign == TextCenter {
 		textAlign |= nanovgo.AlignCenter
-		x += l.LayoutGetWidth() / 2
+		x += w / 2
 	} else {
 		textAlign |= nanovgo.AlignLeft
 	}
 
-	if s.textBaseline == TextTop {
-		textAlign |= nanovgo.AlignTop
-	} else if s.textBaseline == TextBottom {
-		textAlign |= nanovgo.AlignBottom
-		y += l.LayoutGetHeight()
-	} else {
-		textAlign |= nanovgo.AlignMiddle
-		y += l.LayoutGetHeight() / 2
+	if s.textBaseline == TextBottom {
+		y += h - textHeight
+	} else if s.textBaseline != TextTop {
+		y += (h - textHeight) / 2
 	}
 
 	col := s.color
 	if col == nil {
-		col = defaultColor
+		col = s.theme.color("text.primary")
 	}
 	// TODO
 	//ctx.SetTextLetterSpacing()
 	//ctx.SetFontBlur()
 	ctx.SetTextAlign(textAlign)
 	ctx.SetFillColor(colorToNanoColor(col))
-	ctx.SetFontFace(getFontFamily(s.fontFamily))
-	ctx.SetFontSize(float32(getFontSize(s.fontSize)))
-	ctx.Text(x, y, text)
+
+	// center the font inside every line, like the half-leading in css
+	y += (layout.lineHeight - layout.fontHeight) / 2
+	for _, line := range layout.lines {
+		ctx.Text(x, y, line.text)
+		y += layout.lineHeight
+	}
+}
+
+func drawImage(ctx canvas, res resource, parentX, parentY float32, l *flex.Node, s Styles) {
+	// the image is fitted inside the content box
+	x, y, w, h := contentBox(parentX, parentY, l)
+
+	imgWidth, imgHeight := float32(res.width), float32(res.height)
+	if w <= 0 || h <= 0 || imgWidth == 0 || imgHeight == 0 {
+		return
+	}
+
+	scaleX, scaleY := w/imgWidth, h/imgHeight
+	switch ObjectFit(checkUnset(int(s.objectFit), int(ObjectFitFill))) {
+	case ObjectFitContain:
+		scaleX = minF(scaleX, scaleY)
+		scaleY = scaleX
+	case ObjectFitCover:
+		scaleX = maxF(scaleX, scaleY)
+		scaleY = scaleX
+	case ObjectFitNone:
+		scaleX, scaleY = 1, 1
+	}
+
+	// center the image and only fill the part of it that is inside the content box
+	imgWidth *= scaleX
+	imgHeight *= scaleY
+	imgX := x + (w-imgWidth)/2
+	imgY := y + (h-imgHeight)/2
+
+	left, top := maxF(x, imgX), maxF(y, imgY)
+	right, bottom := minF(x+w, imgX+imgWidth), minF(y+h, imgY+imgHeight)
+
+	ctx.BeginPath()
+	ctx.SetFillPaint(imagePattern(imgX, imgY, imgWidth, imgHeight, res.image, 1))
+	roundedRect(ctx, left, top, right-left, bottom-top, radii(s, right-left, bottom-top))
+	ctx.Fill()
+}
+
+// drawPlaceholder fills the content box while an image is loading, and crosses it out if the loading failed
+func drawPlaceholder(ctx canvas, parentX, parentY float32, l *flex.Node, s Styles, failed bool) {
+	x, y, w, h := contentBox(parentX, parentY, l)
+
+	ctx.BeginPath()
+	ctx.SetFillColor(colorToNanoColor(s.theme.color("surface.subtle")))
+	roundedRect(ctx, x, y, w, h, radii(s, w, h))
+	ctx.Fill()
+
+	if failed {
+		ctx.BeginPath()
+		ctx.MoveTo(x, y)
+		ctx.LineTo(x+w, y+h)
+		ctx.MoveTo(x+w, y)
+		ctx.LineTo(x, y+h)
+		ctx.SetStrokeColor(colorToNanoColor(s.theme.color("error")))
+		ctx.SetStrokeWidth(2)
+		ctx.Stroke()
+	}
+}
+
+// contentBox returns the absolute position and size of the area inside the border and padding
+func contentBox(parentX, parentY float32, l *flex.Node) (x, y, w, h float32) {
+	x = parentX + l.LayoutGetLeft() + inset(l, flex.EdgeLeft)
+	y = parentY + l.LayoutGetTop() + inset(l, flex.EdgeTop)
+	w = l.LayoutGetWidth() - inset(l, flex.EdgeLeft) - inset(l, flex.EdgeRight)
+	h = l.LayoutGetHeight() - inset(l, flex.EdgeTop) - inset(l, flex.EdgeBottom)
+	return x, y, w, h
+}
+
+// paddingBox returns the absolute position and size of the area inside the border
+func paddingBox(parentX, parentY float32, l *flex.Node) (x, y, w, h float32) {
+	x = parentX + l.LayoutGetLeft() + l.LayoutGetBorder(flex.EdgeLeft)
+	y = parentY + l.LayoutGetTop() + l.LayoutGetBorder(flex.EdgeTop)
+	w = l.LayoutGetWidth() - l.LayoutGetBorder(flex.EdgeLeft) - l.LayoutGetBorder(flex.EdgeRight)
+	h = l.LayoutGetHeight() - l.LayoutGetBorder(flex.EdgeTop) - l.LayoutGetBorder(flex.EdgeBottom)
+	return x, y, w, h
+}
+
+// inset returns the distance from an edge of the box to the content, the border and the padding
+func inset(l *flex.Node, edge flex.Edge) float32 {
+	return l.LayoutGetBorder(edge) + l.LayoutGetPadding(edge)
+}
+
+func minF(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxF(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
 }
 
 func colorToNanoColor(c color.Color) nanovgo.Color {
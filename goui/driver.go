@@ -0,0 +1,222 @@
+package goui
+
+import (
+	"image"
+	"time"
+)
+
+// Driver runs a UI without a window, with the software renderer, so that it can be used in tests.
+// Events are queued and nothing happens until Step is called, which handles the queued events in order
+// and renders a new frame if the UI changed.
+// Time stands still for animations until Advance is called.
+type Driver struct {
+	g   *gui
+	ctx *softCanvas
+	now time.Time
+}
+
+// NewDriver creates a UI of the given size and renders the first frame
+func NewDriver(width, height int, render func(ui UI)) (*Driver, error) {
+	ctx := newSoftCanvas(width, height)
+	if err := loadFonts(ctx); err != nil {
+		return nil, err
+	}
+
+	g := newGUI(ctx, render)
+	g.width, g.height = width, height
+	d := &Driver{g: g, ctx: ctx, now: time.Unix(0, 0)}
+	g.clock = func() time.Time { return d.now }
+
+	g.Rerender()
+	d.Step()
+	return d, nil
+}
+
+// Step handles all queued events and renders a frame if the UI has to be rendered again.
+// Images are loaded before it returns, so every step gives the same result.
+func (d *Driver) Step() {
+	if d.g.handleEvents() {
+		d.g.Rerender()
+	}
+	if !d.g.takeRerender() {
+		return
+	}
+
+	for {
+		d.ctx.clear()
+		d.g.render(d.g.width, d.g.height)
+		if !d.g.resources.loading() {
+			return
+		}
+		// wait for a resource to load and render again
+		<-d.g.queueRender
+	}
+}
+
+// Advance moves the time of transitions, animations and the blinking caret forward, and renders a frame
+// if something is animating or the caret blinks
+func (d *Driver) Advance(duration time.Duration) {
+	d.now = d.now.Add(duration)
+	if d.g.animating || (!d.g.caretBlink.IsZero() && !d.now.Before(d.g.caretBlink)) {
+		d.g.Rerender()
+	}
+	d.Step()
+}
+
+// Key queues a key event
+func (d *Driver) Key(ev KeyEvent) {
+	d.g.queueKey(ev)
+}
+
+// Text queues a typed character
+func (d *Driver) Text(char rune) {
+	d.g.queueText(char)
+}
+
+// Type queues every character of the text, as if it was typed
+func (d *Driver) Type(text string) {
+	for _, char := range text {
+		d.Text(char)
+	}
+}
+
+// MoveMouse queues a mouse move to the given position
+func (d *Driver) MoveMouse(x, y float64) {
+	d.g.queueMouseMove(MouseMoveEvent{X: x, Y: y})
+}
+
+// Press queues a press of a mouse button
+func (d *Driver) Press(ev ClickEvent) {
+	d.g.queuePress(ev)
+}
+
+// Release queues a release of a mouse button
+func (d *Driver) Release(ev ClickEvent) {
+	d.g.queueRelease(ev)
+}
+
+// Click queues a move of the mouse to the position of the event, followed by a press and a release
+func (d *Driver) Click(ev ClickEvent) {
+	d.MoveMouse(ev.X, ev.Y)
+	d.Press(ev)
+	d.Release(ev)
+}
+
+// Scroll queues a scroll event, which scrolls at the position of the mouse like with a window
+func (d *Driver) Scroll(ev ScrollEvent) {
+	d.g.queueScroll(ev)
+}
+
+// Resize queues a change of the size of the UI
+func (d *Driver) Resize(width, height int) {
+	d.g.queue(func() bool {
+		d.ctx.resize(width, height)
+		d.g.width, d.g.height = width, height
+		if d.g.resizeCb != nil {
+			d.g.resizeCb(ResizeEvent{Width: width, Height: height})
+		}
+		return true
+	})
+}
+
+// Close cancels the context of the UI, like closing a window
+func (d *Driver) Close() {
+	d.g.cancel()
+}
+
+// Image returns the last rendered frame. The image is reused by the next frame, copy it to keep it.
+func (d *Driver) Image() *image.RGBA {
+	return d.ctx.img
+}
+
+// WidgetInfo describes a widget in the last rendered frame
+type WidgetInfo struct {
+	Key  string
+	Text string // text of texts and buttons, or the text in an input
+
+	// position and size in the window, before Translate, Scale and Rotate are applied
+	X, Y, Width, Height float32
+
+	// styles after the defaults of the widget and the variants of its state are applied,
+	// with the values of its transitions and animations at the time of the frame
+	Styles Styles
+
+	Hovered  bool
+	Active   bool
+	Focused  bool
+	Disabled bool
+
+	transform transform
+}
+
+// Center returns the middle of the widget in the window, to click on it
+func (w WidgetInfo) Center() (float64, float64) {
+	t := w.transform
+	if t == (transform{}) {
+		t = identityTransform
+	}
+	x, y := t.apply(w.X+w.Width/2, w.Y+w.Height/2)
+	return float64(x), float64(y)
+}
+
+// Widgets returns all widgets of the last frame in document order, without the root box
+func (d *Driver) Widgets() []WidgetInfo {
+	var widgets []WidgetInfo
+	if d.g.root == nil {
+		return widgets
+	}
+
+	d.g.root.walk(func(w *widgetContainer) {
+		if w == d.g.root {
+			return
+		}
+		widgets = append(widgets, WidgetInfo{
+			Key:      w.handle.key,
+			Text:     widgetText(w),
+			X:        w.x,
+			Y:        w.y,
+			Width:    w.width,
+			Height:   w.height,
+			Styles:   w.handle.styles,
+			Hovered:  w.state&stateHover != 0,
+			Active:   w.state&stateActive != 0,
+			Focused:  w.state&stateFocus != 0,
+			Disabled: w.state&stateDisabled != 0,
+
+			transform: w.transform,
+		})
+	})
+	return widgets
+}
+
+// Find returns the first widget with the given key
+func (d *Driver) Find(key string) (WidgetInfo, bool) {
+	for _, w := range d.Widgets() {
+		if w.Key == key {
+			return w, true
+		}
+	}
+	return WidgetInfo{}, false
+}
+
+// FindText returns the first text, button or input with the given text
+func (d *Driver) FindText(text string) (WidgetInfo, bool) {
+	for _, w := range d.Widgets() {
+		if w.Text == text {
+			return w, true
+		}
+	}
+	return WidgetInfo{}, false
+}
+
+func widgetText(w *widgetContainer) string {
+	switch widget := w.widget.(type) {
+	case *textWidget:
+		return widget.text
+	case *buttonWidget:
+		return widget.text
+	case *inputWidget:
+		return widget.state.Text
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+package goui
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"testing"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+func newTestDriver(t *testing.T, width, height int, render func(g UI)) *Driver {
+	t.Helper()
+	d, err := NewDriver(width, height, render)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func find(t *testing.T, d *Driver, key string) WidgetInfo {
+	t.Helper()
+	w, ok := d.Find(key)
+	if !ok {
+		t.Fatalf("no widget with key %q", key)
+	}
+	return w
+}
+
+func findText(t *testing.T, d *Driver, text string) WidgetInfo {
+	t.Helper()
+	w, ok := d.FindText(text)
+	if !ok {
+		t.Fatalf("no widget with text %q", text)
+	}
+	return w
+}
+
+func TestDriverClick(t *testing.T) {
+	count := 0
+	d := newTestDriver(t, 200, 200, func(g UI) {
+		g.Text(fmt.Sprint("count ", count)).Styles(NewStyles().Height(30))
+		g.Button("More").
+			Styles(NewStyles().Width(100).Height(40)).
+			Click(func(ev ClickEvent) { count++ })
+	})
+
+	button := findText(t, d, "More")
+	if button.X != 0 || button.Y != 30 || button.Width != 100 || button.Height != 40 {
+		t.Fatalf("button at %v, %v with size %v, %v", button.X, button.Y, button.Width, button.Height)
+	}
+
+	x, y := button.Center()
+	d.Click(ClickEvent{X: x, Y: y})
+	d.Step()
+	findText(t, d, "count 1")
+
+	button = findText(t, d, "More")
+	if !button.Hovered || !button.Focused {
+		t.Errorf("clicked button is not hovered and focused: %+v", button)
+	}
+	if button.Styles.background != DarkTheme.Colors["surface.hover"] {
+		t.Errorf("hovered button has background %v", button.Styles.background)
+	}
+
+	d.MoveMouse(150, 150)
+	d.Step()
+	if button = findText(t, d, "More"); button.Hovered || button.Styles.background != DarkTheme.Colors["surface"] {
+		t.Errorf("button is still hovered with background %v", button.Styles.background)
+	}
+}
+
+func TestDriverTyping(t *testing.T) {
+	state := &InputState{}
+	d := newTestDriver(t, 300, 100, func(g UI) {
+		g.Input(state).Key("input").Styles(NewStyles().Width(200))
+	})
+
+	x, y := find(t, d, "input").Center()
+	d.Click(ClickEvent{X: x, Y: y})
+	d.Type("hello")
+	d.Key(KeyEvent{Key: glfw.KeyBackspace, Action: Press})
+	d.Key(KeyEvent{Key: glfw.KeyHome, Action: Press})
+	d.Text('>')
+	d.Step()
+
+	if state.Text != ">hell" || state.CursorPos != 1 {
+		t.Fatalf("input has text %q with the caret at %v", state.Text, state.CursorPos)
+	}
+	if input := findText(t, d, ">hell"); !input.Focused || input.Width != 200 {
+		t.Errorf("input is not focused or has width %v", input.Width)
+	}
+}
+
+func TestDriverCaretBlink(t *testing.T) {
+	state := &InputState{Focused: true}
+	d := newTestDriver(t, 200, 50, func(g UI) {
+		g.Input(state)
+	})
+
+	shown := append([]byte{}, d.Image().Pix...)
+	d.Advance(caretBlinkInterval / 2)
+	if !bytes.Equal(shown, d.Image().Pix) {
+		t.Error("the caret blinked before the interval passed")
+	}
+	d.Advance(caretBlinkInterval / 2)
+	if bytes.Equal(shown, d.Image().Pix) {
+		t.Error("the caret did not blink")
+	}
+	d.Advance(caretBlinkInterval)
+	if !bytes.Equal(shown, d.Image().Pix) {
+		t.Error("the caret is not shown again")
+	}
+}
+
+func TestDriverResize(t *testing.T) {
+	var resized ResizeEvent
+	d := newTestDriver(t, 200, 100, func(g UI) {
+		g.OnResize(func(ev ResizeEvent) { resized = ev })
+		width, height := g.Size()
+		g.Box(func() {}).Key("half").Styles(NewStyles().WidthPct(50).Height(20).Background(color.White))
+		g.Text(fmt.Sprint(width, "x", height))
+	})
+
+	d.Resize(400, 300)
+	d.Step()
+	if resized != (ResizeEvent{Width: 400, Height: 300}) {
+		t.Errorf("resize callback got %v", resized)
+	}
+	findText(t, d, "400x300")
+	if half := find(t, d, "half"); half.Width != 200 {
+		t.Errorf("box with 50%% width is %v wide", half.Width)
+	}
+	if bounds := d.Image().Bounds(); bounds.Dx() != 400 || bounds.Dy() != 300 {
+		t.Errorf("image has size %v", bounds.Size())
+	}
+}
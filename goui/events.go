@@ -14,6 +14,16 @@ type KeyEvent struct {
 	Shift  bool
 	Alt    bool
 	Super  bool
+
+	stopped *bool
+}
+
+// StopPropagation prevents the key event from reaching the parent widgets, the default action
+// of the focused widget and the global OnKey callback
+func (ev KeyEvent) StopPropagation() {
+	if ev.stopped != nil {
+		*ev.stopped = true
+	}
 }
 
 func (ev KeyEvent) String() string {
@@ -33,6 +43,15 @@ type ClickEvent struct {
 	Shift  bool
 	Alt    bool
 	Super  bool
+
+	stopped *bool
+}
+
+// StopPropagation prevents the click from bubbling up to the parent widgets
+func (ev ClickEvent) StopPropagation() {
+	if ev.stopped != nil {
+		*ev.stopped = true
+	}
 }
 
 func (ev ClickEvent) String() string {
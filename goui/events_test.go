@@ -0,0 +1,34 @@
+package goui
+
+import "testing"
+
+func TestQueueEventsWithoutBlocking(t *testing.T) {
+	var typed []rune
+	var moves []MouseMoveEvent
+	g := newGUI(newSoftCanvas(100, 100), func(g UI) {})
+	g.OnText(func(char rune) { typed = append(typed, char) })
+	g.OnMouseMove(func(ev MouseMoveEvent) { moves = append(moves, ev) })
+
+	// more events than a window sends between two frames, queued on the thread that handles them
+	for i := 0; i < 100; i++ {
+		g.queueMouseMove(MouseMoveEvent{X: float64(i), Y: 1})
+	}
+	for _, char := range "typed while the mouse moves" {
+		g.queueText(char)
+	}
+	g.queueMouseMove(MouseMoveEvent{X: 5, Y: 5})
+	g.queueMouseMove(MouseMoveEvent{X: 6, Y: 6})
+
+	if !g.handleEvents() {
+		t.Error("handled events don't render again")
+	}
+	if string(typed) != "typed while the mouse moves" {
+		t.Errorf("typed %q", string(typed))
+	}
+	if len(moves) != 2 || moves[0].X != 99 || moves[1].X != 6 {
+		t.Errorf("mouse moves in a row are not merged into the last one: %v", moves)
+	}
+	if len(g.events) != 0 {
+		t.Errorf("%v events left in the queue", len(g.events))
+	}
+}
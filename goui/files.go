@@ -1,38 +1,250 @@
 package goui
 
 import (
+	"errors"
+	"fmt"
+	"image"
 	"io"
 	"net/http"
 	"os"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-//var resLock
+// the limits of the resource caches, they are read and written atomically so they can be changed while UIs run
+var (
+	resourceIdleTimeout  = int64(5 * time.Minute)
+	resourceMemoryBudget = int64(256 << 20) // bytes
+)
+
+// ResourceIdleTimeout sets how long a loaded resource (like an image) is kept after it was last rendered
+func ResourceIdleTimeout(timeout time.Duration) {
+	atomic.StoreInt64(&resourceIdleTimeout, int64(timeout))
+}
+
+// ResourceMemoryBudget sets how many bytes of loaded resources are kept before the least recently used ones are freed
+func ResourceMemoryBudget(bytes int) {
+	atomic.StoreInt64(&resourceMemoryBudget, int64(bytes))
+}
+
+type resourceState int
+
+const (
+	resourceLoading resourceState = iota
+	resourceLoaded
+	resourceFailed
+)
 
 type resource struct {
+	state    resourceState
+	decoded  image.Image // set by the loading goroutine, uploaded to nanovgo on the render thread when it is used
+	image    int         // nanovgo image handle
+	width    int
+	height   int
+	size     int // approximate memory usage in bytes
 	err      error
 	lastUsed time.Time
+	frame    int  // last frame the resource was rendered in
+	stale    bool // the file changed while it was loading, so it is loaded again when the load finishes
+}
+
+// resourceCache loads resources in the background and frees them again when they are no longer used.
+// Everything except the loading goroutines must be called from the render thread.
+type resourceCache struct {
+	lock      sync.Mutex
+	resources map[string]*resource
+	onLoad    func()
+	onCreate  func(path string) // called for every resource that starts loading, used to watch the files in Debug mode
+	frame     int
+	lastClean time.Time
+}
+
+func newResourceCache(onLoad func()) *resourceCache {
+	return &resourceCache{
+		resources: map[string]*resource{},
+		onLoad:    onLoad,
+	}
+}
+
+// image returns the current state of the image at path and starts loading it if needed
+func (c *resourceCache) image(ctx canvas, path string) resource {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	res, ok := c.resources[path]
+	if !ok {
+		res = &resource{state: resourceLoading}
+		c.resources[path] = res
+		go c.load(path)
+		if c.onCreate != nil {
+			c.onCreate(path)
+		}
+	}
+	res.lastUsed = time.Now()
+	res.frame = c.frame
+
+	// upload decoded images, which has to be done on the render thread
+	if res.state == resourceLoaded && res.decoded != nil {
+		res.image = ctx.CreateImageFromGoImage(0, res.decoded)
+		res.decoded = nil
+		if res.image == 0 {
+			res.fail(path, errors.New("could not create image"))
+		}
+	}
+
+	return *res
+}
+
+// loading returns if any resource used by the latest frame is still being loaded
+func (c *resourceCache) loading() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, res := range c.resources {
+		if res.state == resourceLoading && res.frame == c.frame {
+			return true
+		}
+	}
+	return false
+}
+
+// beginFrame must be called before a new frame is rendered, resources used by the latest frame are never freed
+func (c *resourceCache) beginFrame() {
+	c.frame++
+}
+
+func (c *resourceCache) load(path string) {
+	img, err := decodeImage(path)
+
+	c.lock.Lock()
+	if res, ok := c.resources[path]; ok && res.stale {
+		res.stale = false
+		c.lock.Unlock()
+		c.load(path)
+		return
+	} else if ok {
+		if err != nil {
+			res.fail(path, err)
+		} else {
+			res.state = resourceLoaded
+			res.decoded = img
+			res.width = img.Bounds().Dx()
+			res.height = img.Bounds().Dy()
+			res.size = res.width * res.height * 4
+		}
+	}
+	c.lock.Unlock()
+
+	if c.onLoad != nil {
+		c.onLoad()
+	}
+}
+
+func (r *resource) fail(path string, err error) {
+	logError("could not load image", path+":", err)
+	r.state = resourceFailed
+	r.err = err
+}
+
+// clean frees resources that have not been used for a while, and the least recently used ones
+// when the memory budget is exceeded. Failed resources are freed once they are no longer rendered,
+// so they are loaded again the next time they are used.
+func (c *resourceCache) clean(ctx canvas) {
+	now := time.Now()
+	if now.Sub(c.lastClean) < time.Second {
+		return
+	}
+	c.lastClean = now
+
+	idleTimeout := time.Duration(atomic.LoadInt64(&resourceIdleTimeout))
+	budget := int(atomic.LoadInt64(&resourceMemoryBudget))
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var loaded []string
+	memory := 0
+	for path, res := range c.resources {
+		if res.state == resourceLoading || res.frame == c.frame {
+			memory += res.size
+			continue
+		}
+		if res.state == resourceFailed || now.Sub(res.lastUsed) > idleTimeout {
+			c.free(ctx, path)
+			continue
+		}
+		loaded = append(loaded, path)
+		memory += res.size
+	}
+
+	sort.Slice(loaded, func(i, j int) bool {
+		return c.resources[loaded[i]].lastUsed.Before(c.resources[loaded[j]].lastUsed)
+	})
+	for _, path := range loaded {
+		if memory <= budget {
+			break
+		}
+		memory -= c.resources[path].size
+		c.free(ctx, path)
+	}
+}
+
+// evict frees the resource, so that it is loaded again the next time it is used.
+// A resource that is still loading is loaded again once it is done instead.
+func (c *resourceCache) evict(ctx canvas, path string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	res, ok := c.resources[path]
+	if !ok {
+		return
+	}
+	if res.state == resourceLoading {
+		res.stale = true
+		return
+	}
+	c.free(ctx, path)
 }
 
-func init() {
-	//go func() {
-	//	for {
-	//		select {
-	//
-	//		}
-	//	}
-	//}()
+func (c *resourceCache) free(ctx canvas, path string) {
+	if res := c.resources[path]; res.image != 0 {
+		ctx.DeleteImage(res.image)
+	}
+	delete(c.resources, path)
+}
+
+func decodeImage(path string) (image.Image, error) {
+	file, err := loadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	return img, err
 }
 
-var loadedResources = map[string]resource{}
+// httpClient loads images from URLs, the timeout makes sure a server that doesn't respond can't keep
+// an image loading forever
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+func isURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
 
 func loadFile(path string) (io.ReadCloser, error) {
-	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
-		resp, err := http.Get(path)
+	if isURL(path) {
+		resp, err := httpClient.Get(path)
 		if err != nil {
 			return nil, err
 		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("unexpected status: %v", resp.Status)
+		}
 		return resp.Body, nil
 	}
 
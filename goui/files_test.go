@@ -0,0 +1,89 @@
+package goui
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDriverImageNotRenderedAfterLoading(t *testing.T) {
+	renders := 0
+	done := make(chan *Driver)
+	go func() {
+		d, err := NewDriver(100, 100, func(g UI) {
+			renders++
+			if renders == 1 {
+				g.Image("../cat.jpg")
+			}
+		})
+		if err != nil {
+			t.Error(err)
+		}
+		done <- d
+	}()
+
+	select {
+	case d := <-done:
+		if d == nil {
+			return
+		}
+		if renders != 2 {
+			t.Errorf("rendered %v times, expected 2", renders)
+		}
+		if d.g.resources.loading() {
+			t.Error("the image is still loading")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Step blocked on an image that is no longer rendered")
+	}
+}
+
+func TestCleanFreesFailedResources(t *testing.T) {
+	d, err := NewDriver(100, 100, func(g UI) {
+		g.Image("missing.png")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := d.g.resources
+	if c.resources["missing.png"].state != resourceFailed {
+		t.Fatal("expected the image to fail")
+	}
+
+	c.clean(d.ctx)
+	if _, ok := c.resources["missing.png"]; !ok {
+		t.Error("a failed resource of the latest frame was freed")
+	}
+
+	c.beginFrame()
+	c.lastClean = time.Time{}
+	c.clean(d.ctx)
+	if _, ok := c.resources["missing.png"]; ok {
+		t.Error("a failed resource that is no longer rendered was not freed")
+	}
+}
+
+func TestResourceIdleTimeout(t *testing.T) {
+	d, err := NewDriver(100, 100, func(g UI) {
+		g.Image("../cat.jpg")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := d.g.resources
+	c.beginFrame()
+
+	c.lastClean = time.Time{}
+	c.clean(d.ctx)
+	if _, ok := c.resources["../cat.jpg"]; !ok {
+		t.Fatal("a recently used image was freed")
+	}
+
+	defer ResourceIdleTimeout(time.Duration(atomic.LoadInt64(&resourceIdleTimeout)))
+	ResourceIdleTimeout(0)
+	c.lastClean = time.Time{}
+	c.clean(d.ctx)
+	if _, ok := c.resources["../cat.jpg"]; ok {
+		t.Error("an idle image was not freed after the timeout was changed")
+	}
+}
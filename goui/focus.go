@@ -0,0 +1,220 @@
+package goui
+
+import (
+	"sort"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/kjk/flex"
+)
+
+const (
+	defaultFocusRingWidth  = 2
+	defaultFocusRingOffset = 2
+)
+
+// focusable returns if the widget can receive the keyboard focus
+func focusable(w *widgetContainer) bool {
+	for p := w; p != nil; p = p.parent {
+		if p.handle.disabled {
+			return false
+		}
+	}
+	if w.handle.focusable {
+		return true
+	}
+	switch w.widget.(type) {
+	case *inputWidget, *buttonWidget:
+		return true
+	}
+	return false
+}
+
+// Focus moves the keyboard focus to the widget with the given key (see Handle.Key) after the current render.
+// An empty key removes the focus.
+func (g *gui) Focus(key string) {
+	g.focusKey = &key
+}
+
+// focusAt focuses the closest focusable widget to the target, or removes the focus if there is none.
+// It is used for mouse presses, so the focus ring is hidden.
+func (g *gui) focusAt(target *widgetContainer) {
+	g.focusVisible = false
+	for w := target; w != nil; w = w.parent {
+		if focusable(w) {
+			g.setFocus(w)
+			return
+		}
+	}
+	g.setFocus(nil)
+}
+
+func (g *gui) setFocus(w *widgetContainer) {
+	var input *InputState
+	if w != nil {
+		g.focusID = w.id
+		if wid, ok := w.widget.(*inputWidget); ok {
+			input = wid.state
+		}
+	} else {
+		g.focusID = ""
+	}
+	g.focused = w
+
+	if g.focusedInput != input {
+		if g.focusedInput != nil {
+			g.focusedInput.focus(false)
+		}
+		if input != nil {
+			input.focus(true)
+			input.restartBlink(g.clock())
+		}
+		g.focusedInput = input
+	}
+}
+
+// syncFocus finds the focused widget in a new widget tree. An input that was focused with
+// InputState.Focused takes the focus, and the focus is lost when the focused widget is no longer rendered.
+func (g *gui) syncFocus() {
+	var byID, byInput *widgetContainer
+	g.root.walk(func(w *widgetContainer) {
+		if input, ok := w.widget.(*inputWidget); ok && g.focusedInput != nil && input.state == g.focusedInput {
+			byInput = w
+		}
+		if w.id == g.focusID {
+			byID = w
+		}
+	})
+
+	focused := byInput
+	if focused == nil && byID != nil {
+		if _, isInput := byID.widget.(*inputWidget); !isInput {
+			focused = byID
+		}
+	}
+	if focused != nil && !focusable(focused) {
+		focused = nil
+	}
+
+	// focus requested with UI.Focus
+	if g.focusKey != nil {
+		focused = nil
+		g.root.walk(func(w *widgetContainer) {
+			if focused == nil && *g.focusKey != "" && w.handle.key == *g.focusKey && focusable(w) {
+				focused = w
+			}
+		})
+		g.focusKey = nil
+		g.focusVisible = true
+		g.scrollToFocus = true
+	}
+
+	g.setFocus(focused)
+}
+
+// tabOrder returns the widgets that can be reached with tab. Like in html, widgets with a positive
+// tab index come first, followed by the rest in document order. A negative tab index skips the widget.
+func (g *gui) tabOrder() []*widgetContainer {
+	var indexed, rest []*widgetContainer
+	g.root.walk(func(w *widgetContainer) {
+		if !focusable(w) || w.handle.tabIndex < 0 {
+			return
+		}
+		if w.handle.tabIndex > 0 {
+			indexed = append(indexed, w)
+		} else {
+			rest = append(rest, w)
+		}
+	})
+	sort.SliceStable(indexed, func(i, j int) bool {
+		return indexed[i].handle.tabIndex < indexed[j].handle.tabIndex
+	})
+	return append(indexed, rest...)
+}
+
+// moveFocus focuses the next or previous widget in the tab order
+func (g *gui) moveFocus(forward bool) {
+	order := g.tabOrder()
+	if len(order) == 0 {
+		return
+	}
+
+	current := -1
+	for i, w := range order {
+		if w == g.focused {
+			current = i
+		}
+	}
+
+	next := 0
+	if forward {
+		next = (current + 1) % len(order)
+	} else if current <= 0 {
+		next = len(order) - 1
+	} else {
+		next = current - 1
+	}
+
+	g.setFocus(order[next])
+	g.focusVisible = true
+	g.scrollToFocus = true
+}
+
+// handleKey sends a key event to the focused widget and its ancestors, then does the default action
+// of the focused widget and moves the focus on tab. The global OnKey callback is called last.
+// Call KeyEvent.StopPropagation in a handler to stop all of this.
+func (g *gui) handleKey(ev KeyEvent) bool {
+	if ev.stopped == nil {
+		ev.stopped = new(bool)
+	}
+	handled := false
+
+	for w := g.focused; w != nil; w = w.parent {
+		if w.handle.onKey == nil {
+			continue
+		}
+		w.handle.onKey(ev)
+		handled = true
+		if *ev.stopped {
+			return handled
+		}
+	}
+
+	if g.handleInputKey(ev) || g.activateFocused(ev) {
+		handled = true
+	} else if ev.Key == glfw.KeyTab && ev.Action != Release && g.root != nil {
+		g.moveFocus(!ev.Shift)
+		handled = true
+	}
+
+	if g.keyCb != nil {
+		g.keyCb(ev)
+		handled = true
+	}
+	return handled
+}
+
+// drawFocusRing draws an outline around the focused widget
+func drawFocusRing(ctx canvas, parentX, parentY float32, l *flex.Node, s Styles) {
+	width := checkUnsetF(s.focusRingWidth, defaultFocusRingWidth)
+	if width <= 0 {
+		return
+	}
+	col := s.focusRingColor
+	if col == nil {
+		col = s.theme.color("focus")
+	}
+
+	offset := float32(defaultFocusRingOffset) + width/2
+	x := parentX + l.LayoutGetLeft() - offset
+	y := parentY + l.LayoutGetTop() - offset
+	r := radii(s, l.LayoutGetWidth(), l.LayoutGetHeight())
+	for i := range r {
+		r[i] += offset
+	}
+
+	ctx.BeginPath()
+	roundedRect(ctx, x, y, l.LayoutGetWidth()+2*offset, l.LayoutGetHeight()+2*offset, r)
+	ctx.SetStrokeColor(colorToNanoColor(col))
+	ctx.SetStrokeWidth(width)
+	ctx.Stroke()
+}
@@ -1,13 +1,16 @@
 package goui
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"image"
 	_ "image/gif"
 	_ "image/jpeg"
 	_ "image/png"
 	"io/ioutil"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/go-gl/glfw/v3.3/glfw"
@@ -16,13 +19,22 @@ import (
 )
 
 type UI interface {
-	//Button(text string) *Handle
-	// Input() *Handle
+	Button(text string) *Handle
+	Input(state *InputState) *Handle
 	Text(text string) *Handle
+	Image(path string) *Handle
 	Box(children func()) *Handle
+	KeyedBox(key string, children func()) *Handle
+	State(key string, init func() interface{}) interface{}
+	Focus(key string)
 	Rerender()
+	Post(fn func())
+	Context() context.Context
 	Title(title string)
 	Size() (int, int)
+	Stylesheet(sheet Stylesheet)
+	Theme() *Theme
+	SetTheme(theme *Theme)
 	Quit()
 
 	OnKey(func(ev KeyEvent))
@@ -46,8 +58,47 @@ type gui struct {
 	root       *widgetContainer
 	currentBox *widgetContainer
 
-	ctx         *nanovgo.Context
-	queueRender chan struct{}
+	ctx         canvas
+	queueRender chan struct{} // holds at most one render request, see Rerender
+	resources   *resourceCache
+
+	postLock sync.Mutex
+	posted   []func() // functions to run on the UI thread, see Post
+
+	context context.Context // cancelled when the window is closed
+	cancel  context.CancelFunc
+
+	width, height int // size of the last render
+
+	renderPending bool      // a render was requested but the next frame is not due yet
+	nextFrame     time.Time // earliest time of the next frame, limited by the frame rate
+
+	clock     func() time.Time // time.Now, except in a Driver
+	frameTime time.Time        // time of the current render, which animations are at
+	animating bool             // the last render has transitions or animations that are not finished
+
+	states *stateStore
+
+	stylesheets    []Stylesheet    // given to Stylesheet during the current render
+	missingClasses map[string]bool // classes that were warned about
+	watcher        *fileWatcher    // watches the files to reload in Debug mode, nil otherwise
+	theme          *Theme
+
+	mouseX, mouseY float32
+	hovered        map[string]bool // ids of the widgets under the cursor
+	pressed        map[string]bool // ids of the widgets the mouse was pressed on
+	pressButton    MouseButton
+	drag           *scrollDrag
+
+	focusID       string
+	focused       *widgetContainer
+	focusedInput  *InputState
+	focusKey      *string // focus requested with UI.Focus
+	focusVisible  bool
+	scrollToFocus bool
+	caretBlink    time.Time
+
+	events []queuedEvent // events of the window, handled in order by handleEvents
 
 	keyCb       func(KeyEvent)
 	textCb      func(rune)
@@ -60,6 +111,30 @@ type gui struct {
 	scrollCb    func(ScrollEvent)
 }
 
+var targetFrameRate = 60
+
+// FrameRate sets how many frames per second are rendered at most, which matters when the UI
+// is rendered continuously like during animations. 0 renders as fast as possible.
+func FrameRate(fps int) {
+	targetFrameRate = fps
+}
+
+func newGUI(ctx canvas, render func(ui UI)) *gui {
+	g := &gui{
+		renderFunc:  render,
+		queueRender: make(chan struct{}, 1),
+		ctx:         ctx,
+		states:      newStateStore(),
+		clock:       time.Now,
+
+		missingClasses: map[string]bool{},
+		theme:          DarkTheme,
+	}
+	g.resources = newResourceCache(g.Rerender)
+	g.context, g.cancel = context.WithCancel(context.Background())
+	return g
+}
+
 func Render(render func(ui UI)) error {
 	// create window
 	window, err := createWindow(1200, 800, "Goui")
@@ -75,116 +150,95 @@ func Render(render func(ui UI)) error {
 	}
 	defer ctx.Delete()
 
-	g := &gui{
-		window:      window,
-		renderFunc:  render,
-		queueRender: make(chan struct{}, 100),
-		ctx:         ctx,
-	}
-
-	keyChannel := make(chan KeyEvent, 10)
-	textChannel := make(chan rune, 10)
-	clickChannel := make(chan ClickEvent, 10)
-	resizeChannel := make(chan ResizeEvent, 10)
-	posChannel := make(chan PositionEvent, 10)
-	focusChannel := make(chan bool, 10)
-	maximizedChannel := make(chan bool, 10)
-	mouseMoveChannel := make(chan MouseMoveEvent, 10)
-	scrollChannel := make(chan ScrollEvent, 10)
+	g := newGUI(nanovgoCanvas{ctx}, render)
+	g.window = window
+	g.width, g.height = window.GetSize()
+	defer g.cancel()
 
+	// the callbacks are called on the main thread while it waits for events, so they only queue the
+	// events for handleEvents and never block
 	window.SetKeyCallback(func(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
-		keyChannel <- KeyEvent{
+		g.queueKey(KeyEvent{
 			Action: Action(action),
 			Key:    key,
 			Ctrl:   mods&glfw.ModControl != 0,
 			Shift:  mods&glfw.ModShift != 0,
 			Alt:    mods&glfw.ModAlt != 0,
 			Super:  mods&glfw.ModSuper != 0,
-		}
+		})
 	})
 
 	window.SetCharCallback(func(w *glfw.Window, char rune) {
-		textChannel <- char
+		g.queueText(char)
 	})
 
 	window.SetPosCallback(func(w *glfw.Window, x int, y int) {
-		posChannel <- PositionEvent{X: x, Y: y}
+		g.queuePosition(PositionEvent{X: x, Y: y})
 	})
 
 	window.SetFocusCallback(func(w *glfw.Window, focused bool) {
-		focusChannel <- focused
+		g.queueFocus(focused)
 	})
 
 	window.SetMaximizeCallback(func(w *glfw.Window, maximized bool) {
-		maximizedChannel <- maximized
+		g.queueMaximize(maximized)
 	})
 
 	window.SetCursorPosCallback(func(w *glfw.Window, x float64, y float64) {
-		mouseMoveChannel <- MouseMoveEvent{X: x, Y: y}
+		g.queueMouseMove(MouseMoveEvent{X: x, Y: y})
 	})
 
 	window.SetScrollCallback(func(w *glfw.Window, x float64, y float64) {
-		scrollChannel <- ScrollEvent{X: x, Y: y}
+		g.queueScroll(ScrollEvent{X: x, Y: y})
 	})
 
 	window.SetSizeCallback(func(w *glfw.Window, width, height int) {
-		resizeChannel <- ResizeEvent{
+		g.queueResize(ResizeEvent{
 			Width:  width,
 			Height: height,
-		}
+		})
 		g.Rerender()
 	})
 
 	window.SetMouseButtonCallback(func(w *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
 		x, y := w.GetCursorPos()
+		ev := ClickEvent{
+			Button: MouseButton(button),
+			X:      x,
+			Y:      y,
+			Ctrl:   mods&glfw.ModControl != 0,
+			Shift:  mods&glfw.ModShift != 0,
+			Alt:    mods&glfw.ModAlt != 0,
+			Super:  mods&glfw.ModSuper != 0,
+		}
 		if action == glfw.Press {
-			clickChannel <- ClickEvent{
-				Button: MouseButton(button),
-				X:      x,
-				Y:      y,
-				Ctrl:   mods&glfw.ModControl != 0,
-				Shift:  mods&glfw.ModShift != 0,
-				Alt:    mods&glfw.ModAlt != 0,
-				Super:  mods&glfw.ModSuper != 0,
-			}
+			g.queuePress(ev)
 		} else {
-			// ???
+			g.queueRelease(ev)
 		}
 	})
 
-	fonts := []string{
-		FontRegular, FontItalic, FontBold, FontBoldItalic,
-		FontMonoRegular, FontMonoItalic, FontMonoBold, FontMonoBoldItalic,
+	if err := loadFonts(g.ctx); err != nil {
+		return err
 	}
 
-	for _, font := range fonts {
-		path := filepath.Join("fonts", font+".ttf")
-		data, err := ioutil.ReadFile(path)
-		if err != nil {
-			return fmt.Errorf("could not load font: %v", err)
-		}
-		id := ctx.CreateFontFromMemory(font, data, 0)
-		if id == -1 {
-			return errors.New("could not load font")
-		}
+	if debug {
+		g.startReloading()
 	}
 
+	window.SetRefreshCallback(func(w *glfw.Window) {
+		g.Rerender()
+	})
+
 	// queue initial render
 	g.Rerender()
 
 	for !window.ShouldClose() {
-		shouldRender := false
-	loop:
-		for {
-			select {
-			case <-g.queueRender:
-				shouldRender = true
-			default:
-				break loop
-			}
+		if g.takeRerender() {
+			g.renderPending = true
 		}
 
-		if shouldRender {
+		if now := time.Now(); g.renderPending && !now.Before(g.nextFrame) {
 			fbWidth, fbHeight := window.GetFramebufferSize()
 			winWidth, winHeight := window.GetSize()
 			pixelRatio := float32(fbWidth) / float32(winWidth)
@@ -196,78 +250,244 @@ func Render(render func(ui UI)) error {
 
 			ctx.EndFrame()
 			glEndFrame()
-		}
+			window.SwapBuffers()
 
-		needRerender := false
-	events:
-		for {
-			select {
-			case ev := <-keyChannel:
-				if g.keyCb != nil {
-					g.keyCb(ev)
-					needRerender = true
-				}
-			case ev := <-textChannel:
-				if g.textCb != nil {
-					g.textCb(ev)
-					needRerender = true
-				}
-			case ev := <-clickChannel:
-				if g.clickCb != nil {
-					g.clickCb(ev)
-					needRerender = true
-				}
-			case ev := <-resizeChannel:
-				if g.resizeCb != nil {
-					g.resizeCb(ev)
-					needRerender = true
-				}
-			case ev := <-posChannel:
-				if g.positionCb != nil {
-					g.positionCb(ev)
-					needRerender = true
-				}
-			case ev := <-focusChannel:
-				if g.focusCb != nil {
-					g.focusCb(ev)
-					needRerender = true
-				}
-			case ev := <-maximizedChannel:
-				if g.maximizeCb != nil {
-					g.maximizeCb(ev)
-					needRerender = true
-				}
-			case ev := <-mouseMoveChannel:
-				if g.mouseMoveCb != nil {
-					g.mouseMoveCb(ev)
-					needRerender = true
-				}
-			case ev := <-scrollChannel:
-				if g.scrollCb != nil {
-					g.scrollCb(ev)
-					needRerender = true
-				}
-			default:
-				break events
+			// keep rendering frames while something is animating
+			g.renderPending = g.animating
+			if targetFrameRate > 0 {
+				g.nextFrame = now.Add(time.Second / time.Duration(targetFrameRate))
 			}
 		}
 
+		needRerender := g.handleEvents()
+
+		// keep the caret blinking
+		if !g.caretBlink.IsZero() && time.Now().After(g.caretBlink) {
+			needRerender = true
+		}
+
 		if needRerender {
 			g.Rerender()
 		}
 
-		// TODO
-		time.Sleep(10 * time.Millisecond)
+		g.resources.clean(g.ctx)
 
-		window.SwapBuffers()
+		g.waitEvents()
+	}
+
+	return nil
+}
+
+// waitEvents sleeps until there are events, a render is requested with Rerender,
+// or it is time for the next frame or caret blink
+func (g *gui) waitEvents() {
+	if len(g.queueRender) > 0 {
+		g.renderPending = true
+	}
+
+	wake := g.caretBlink
+	if g.renderPending {
+		wake = g.nextFrame
+	}
+
+	if g.renderPending && wake.IsZero() {
+		// FrameRate(0) renders the next frame right away
+		glfw.PollEvents()
+	} else if wake.IsZero() {
+		glfw.WaitEvents()
+	} else if timeout := time.Until(wake); timeout > 0 {
+		glfw.WaitEventsTimeout(timeout.Seconds())
+	} else {
 		glfw.PollEvents()
 	}
+}
+
+// RenderToImage renders the UI once into an image with the software renderer, without a window or OpenGL.
+// It waits for the images in the UI to load, so the result only depends on the render function.
+// The software renderer draws the same shapes as nanovgo, but anti aliasing, curves and text are
+// rasterized differently, so the image doesn't match a screenshot of the window pixel for pixel.
+func RenderToImage(width, height int, render func(ui UI)) (*image.RGBA, error) {
+	d, err := NewDriver(width, height, render)
+	if err != nil {
+		return nil, err
+	}
+	d.Close()
+	return d.Image(), nil
+}
+
+var fontDirectory = "fonts"
 
+// FontDirectory sets the directory the fonts are loaded from, relative to the working directory by default
+func FontDirectory(dir string) {
+	fontDirectory = dir
+}
+
+var fonts = []string{
+	FontRegular, FontItalic, FontBold, FontBoldItalic,
+	FontMonoRegular, FontMonoItalic, FontMonoBold, FontMonoBoldItalic,
+}
+
+func fontPath(font string) string {
+	return filepath.Join(fontDirectory, font+".ttf")
+}
+
+func loadFonts(ctx canvas) error {
+	for _, font := range fonts {
+		path := fontPath(font)
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("could not load font: %v", err)
+		}
+		id := ctx.CreateFontFromMemory(font, data, 0)
+		if id == -1 {
+			return errors.New("could not load font")
+		}
+	}
 	return nil
 }
 
+// handleEvents processes all queued events and returns if the UI has to be rendered again
+func (g *gui) handleEvents() bool {
+	needRerender := false
+
+	g.postLock.Lock()
+	posted := g.posted
+	g.posted = nil
+	g.postLock.Unlock()
+	for _, fn := range posted {
+		fn()
+	}
+
+	events := g.events
+	g.events = nil
+	for _, ev := range events {
+		if ev.handle() {
+			needRerender = true
+		}
+	}
+	return needRerender
+}
+
+// queuedEvent is an event of the window that waits to be handled. handle returns if the UI has to be rendered again.
+type queuedEvent struct {
+	handle    func() bool
+	mouseMove *MouseMoveEvent // set for mouse moves, which are merged, see queueMouseMove
+}
+
+func (g *gui) queue(handle func() bool) {
+	g.events = append(g.events, queuedEvent{handle: handle})
+}
+
+func (g *gui) queueKey(ev KeyEvent) {
+	g.queue(func() bool { return g.handleKey(ev) })
+}
+
+func (g *gui) queueText(char rune) {
+	g.queue(func() bool {
+		needRerender := g.handleInputText(char)
+		if g.textCb != nil {
+			g.textCb(char)
+			needRerender = true
+		}
+		return needRerender
+	})
+}
+
+func (g *gui) queuePress(ev ClickEvent) {
+	g.queue(func() bool { return g.handlePress(ev) })
+}
+
+func (g *gui) queueRelease(ev ClickEvent) {
+	g.queue(func() bool { return g.handleRelease(ev) })
+}
+
+func (g *gui) queueScroll(ev ScrollEvent) {
+	g.queue(func() bool { return g.dispatchScroll(ev) })
+}
+
+// queueMouseMove queues a move of the cursor. Moves that follow each other without other events in
+// between are merged into the last one, so a fast moving mouse doesn't fill the queue.
+func (g *gui) queueMouseMove(ev MouseMoveEvent) {
+	if n := len(g.events); n > 0 && g.events[n-1].mouseMove != nil {
+		*g.events[n-1].mouseMove = ev
+		return
+	}
+	move := &ev
+	g.events = append(g.events, queuedEvent{
+		mouseMove: move,
+		handle: func() bool {
+			needRerender := g.handleMouseMove(*move)
+			if g.mouseMoveCb != nil {
+				g.mouseMoveCb(*move)
+				needRerender = true
+			}
+			return needRerender
+		},
+	})
+}
+
+func (g *gui) queueResize(ev ResizeEvent) {
+	g.queue(func() bool {
+		if g.resizeCb == nil {
+			return false
+		}
+		g.resizeCb(ev)
+		return true
+	})
+}
+
+func (g *gui) queuePosition(ev PositionEvent) {
+	g.queue(func() bool {
+		if g.positionCb == nil {
+			return false
+		}
+		g.positionCb(ev)
+		return true
+	})
+}
+
+func (g *gui) queueFocus(focused bool) {
+	g.queue(func() bool {
+		if g.focusCb == nil {
+			return false
+		}
+		g.focusCb(focused)
+		return true
+	})
+}
+
+func (g *gui) queueMaximize(maximized bool) {
+	g.queue(func() bool {
+		if g.maximizeCb == nil {
+			return false
+		}
+		g.maximizeCb(maximized)
+		return true
+	})
+}
+
+// takeRerender empties the render queue and returns if a render was requested
+func (g *gui) takeRerender() bool {
+	shouldRender := false
+	for {
+		select {
+		case <-g.queueRender:
+			shouldRender = true
+		default:
+			return shouldRender
+		}
+	}
+}
+
 func (g *gui) render(width, height int) {
+	g.width, g.height = width, height
+	g.frameTime = g.clock()
+	g.stylesheets = nil
+	g.animating = false
+	g.resources.beginFrame()
+
 	// reset gui state
+	g.focusedInput = nil
 	g.root = &widgetContainer{
 		widget: &boxWidget{},
 		handle: &Handle{
@@ -279,34 +499,77 @@ func (g *gui) render(width, height int) {
 
 	// call user provided render function and populate widget tree
 	g.renderFunc(g)
+	assignIDs(g.root)
+	g.syncFocus()
+	g.updateWidgetStates()
 
 	// calculate layout
-	g.applyStyles(g.root)
+	g.applyStyles(g.root, NewStyles())
 	flex.CalculateLayout(g.root.layout, float32(width), float32(height), flex.DirectionLTR)
+	g.updateScroll(g.root)
+	updateBounds(g.root, 0, 0)
+
+	scrolled := false
+	if g.scrollToFocus && g.focused != nil {
+		scrollIntoView(g.focused)
+		scrolled = true
+	}
+	g.scrollToFocus = false
+	g.root.walk(func(w *widgetContainer) {
+		if w.handle.scrollIntoView {
+			scrollIntoView(w)
+			scrolled = true
+		}
+	})
+	if scrolled {
+		updateBounds(g.root, 0, 0)
+	}
+	g.updateHover()
 
 	// render
+	g.ctx.BeginPath()
+	g.ctx.Rect(0, 0, float32(width), float32(height))
+	g.ctx.SetFillColor(colorToNanoColor(g.theme.color("background")))
+	g.ctx.Fill()
 	g.root.widget.render(g.ctx, 0, 0, g.root.layout, g.root.handle.styles)
+
+	// forget the state of widgets that were not rendered
+	g.states.collect()
+	g.caretBlink = g.scheduleCaretBlink()
 }
 
-// apply styles to all flex.Node objects recursively
-func (g *gui) applyStyles(w *widgetContainer) {
+// apply styles to all flex.Node objects recursively, the inherited styles come from the parent
+func (g *gui) applyStyles(w *widgetContainer, parent Styles) {
+	if len(w.handle.classes) > 0 {
+		w.handle.styles = g.classStyles(w)
+	}
+	if styled, ok := w.widget.(styledWidget); ok {
+		w.handle.styles = CombineStyles(styled.defaultStyles(), w.handle.styles)
+	}
+	w.handle.styles = w.handle.styles.resolve(w.state).themed(g.theme).inherit(parent)
+	w.handle.styles = g.animate(w, g.transition(w, w.handle.styles))
+	w.handle.styles.backgroundPaint = w.handle.styles.backgroundPaint.withResource(g)
 	applyStyles(g.ctx, w.widget, w.layout, w.handle.styles)
 	if box, ok := w.widget.(*boxWidget); ok {
 		for _, child := range box.children {
-			g.applyStyles(child)
+			g.applyStyles(child, w.handle.styles)
 		}
 	}
 }
 
-func (g *gui) addWidgetExtra(w widget) (*widgetContainer, *Handle) {
+func (g *gui) addWidgetExtra(w widget, key string) (*widgetContainer, *Handle) {
 	widgetContainer := &widgetContainer{
 		widget: w,
 		layout: flex.NewNode(),
 		handle: &Handle{
 			styles: NewStyles(),
+			key:    key,
 		},
+		parent:   g.currentBox,
+		callSite: callSite(),
 	}
 	parent := g.currentBox.widget.(*boxWidget)
+	widgetContainer.stateID = stateID(g.currentBox.stateID, parent, widgetContainer.callSite, key)
 	// add to parent layout
 	g.currentBox.layout.InsertChild(widgetContainer.layout, len(parent.children))
 	// add to parent box
@@ -315,17 +578,64 @@ func (g *gui) addWidgetExtra(w widget) (*widgetContainer, *Handle) {
 }
 
 func (g *gui) addWidget(w widget) *Handle {
-	_, handle := g.addWidgetExtra(w)
+	_, handle := g.addWidgetExtra(w, "")
 	return handle
 }
 
+// Theme returns the theme of the UI, DarkTheme unless SetTheme was called
+func (g *gui) Theme() *Theme {
+	return g.theme
+}
+
+// SetTheme switches the theme of the UI and renders it again. Styles that use the Token of a color
+// change to the color of the new theme.
+func (g *gui) SetTheme(theme *Theme) {
+	g.theme = theme
+	g.Rerender()
+}
+
+// Rerender requests a new render. Requests are combined until the next frame, so it never blocks.
+// It can be called from any goroutine.
 func (g *gui) Rerender() {
-	g.queueRender <- struct{}{}
+	select {
+	case g.queueRender <- struct{}{}:
+	default:
+	}
+	g.wake()
+}
+
+// Post runs the function on the UI thread before the next render, and renders again afterwards.
+// Use it to change the state of the UI from other goroutines, like after loading data in the background.
+// It can be called from any goroutine and never blocks.
+func (g *gui) Post(fn func()) {
+	g.runOnUIThread(func() {
+		fn()
+		g.Rerender()
+	})
+}
+
+func (g *gui) runOnUIThread(fn func()) {
+	g.postLock.Lock()
+	g.posted = append(g.posted, fn)
+	g.postLock.Unlock()
+	g.wake()
+}
+
+// wake makes the event loop stop waiting for events, glfw allows this from any goroutine
+func (g *gui) wake() {
+	if g.window != nil {
+		glfw.PostEmptyEvent()
+	}
+}
+
+// Context returns a context that is cancelled when the window is closed,
+// to stop the work of goroutines that were started by the UI
+func (g *gui) Context() context.Context {
+	return g.context
 }
 
 func (g *gui) OnKey(callback func(ev KeyEvent))                 { g.keyCb = callback }
 func (g *gui) OnText(callback func(char rune))                  { g.textCb = callback }
-func (g *gui) OnClick(callback func(ev ClickEvent))             { g.clickCb = callback }
 func (g *gui) OnResize(callback func(ev ResizeEvent))           { g.resizeCb = callback }
 func (g *gui) OnPositionChange(callback func(ev PositionEvent)) { g.positionCb = callback }
 func (g *gui) OnFocusChange(callback func(focused bool))        { g.focusCb = callback }
@@ -333,6 +643,33 @@ func (g *gui) OnMaximizeChange(callback func(maximized bool))   { g.maximizeCb =
 func (g *gui) OnMouseMove(callback func(ev MouseMoveEvent))     { g.mouseMoveCb = callback }
 func (g *gui) OnScroll(callback func(ev ScrollEvent))           { g.scrollCb = callback }
 
-func (g *gui) Quit()              { g.window.SetShouldClose(true) }
-func (g *gui) Title(title string) { g.window.SetTitle(title) }
-func (g *gui) Size() (int, int)   { return g.window.GetSize() }
+// OnClick sets the callback for clicks in the window. Like the click handlers of widgets, it is called
+// when the mouse button is released, not when it is pressed, and only if the button was pressed inside
+// the window. It is called after the handlers of the widgets under the cursor, unless one of them calls
+// ClickEvent.StopPropagation or the click is on a disabled widget.
+func (g *gui) OnClick(callback func(ev ClickEvent)) { g.clickCb = callback }
+
+// the window is nil when rendering without a window. Most of glfw can only be used from the main thread,
+// so Quit and Title work from any goroutine by running on the UI thread.
+
+func (g *gui) Quit() {
+	g.runOnUIThread(func() {
+		if g.window != nil {
+			g.window.SetShouldClose(true)
+		}
+		g.cancel()
+	})
+}
+
+func (g *gui) Title(title string) {
+	g.runOnUIThread(func() {
+		if g.window != nil {
+			g.window.SetTitle(title)
+		}
+	})
+}
+
+// Size returns the size of the window, it must be called on the UI thread
+func (g *gui) Size() (int, int) {
+	return g.width, g.height
+}
@@ -0,0 +1,147 @@
+// Package gouitest compares rendered UIs to golden images stored as PNG files.
+//
+// Goldens are stored in testdata/<name>.png next to the test. Run the tests with -update
+// to create or refresh them:
+//
+//	go test ./... -update
+//
+// To test a UI after interacting with it, drive it with goui.NewDriver and compare the frame with CompareImage.
+//
+// The images are drawn by the software renderer of goui.RenderToImage, so goldens can't be compared
+// to screenshots of a window, which is drawn by the GPU.
+//
+// Fonts are loaded from the fonts directory in the working directory, which is the directory
+// of the package under test. Use goui.FontDirectory in TestMain to load them from somewhere else.
+package gouitest
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"../../goui"
+)
+
+var update = flag.Bool("update", false, "update the golden images instead of comparing to them")
+
+// Tolerance is how much every color channel of a pixel, from 0 to 255, can differ from the golden image.
+// Anti aliasing and font rendering can differ slightly between machines.
+var Tolerance uint8 = 2
+
+// Dir is the directory the golden images are stored in
+var Dir = "testdata"
+
+// Snapshot renders the UI at the given size without a window and compares it to the golden image with the given name
+func Snapshot(t testing.TB, name string, width, height int, render func(ui goui.UI)) {
+	t.Helper()
+
+	img, err := goui.RenderToImage(width, height, render)
+	if err != nil {
+		t.Fatalf("could not render %v: %v", name, err)
+	}
+	CompareImage(t, name, img)
+}
+
+// CompareImage compares an image to the golden image with the given name. If they differ, an image
+// with the differing pixels in red is written next to the golden and the test fails.
+func CompareImage(t testing.TB, name string, img image.Image) {
+	t.Helper()
+
+	goldenPath := filepath.Join(Dir, name+".png")
+	diffPath := filepath.Join(Dir, name+".diff.png")
+
+	if *update {
+		if err := writePNG(goldenPath, img); err != nil {
+			t.Fatalf("could not update golden %v: %v", goldenPath, err)
+		}
+		os.Remove(diffPath)
+		return
+	}
+
+	golden, err := readPNG(goldenPath)
+	if err != nil {
+		t.Fatalf("could not read golden %v, run the test with -update to create it: %v", goldenPath, err)
+	}
+
+	diff, count := Diff(golden, img, Tolerance)
+	if count == 0 {
+		os.Remove(diffPath)
+		return
+	}
+
+	if err := writePNG(diffPath, diff); err != nil {
+		t.Errorf("could not write diff %v: %v", diffPath, err)
+	}
+	t.Errorf("%v: %v pixels differ from the golden image, see %v", name, count, diffPath)
+}
+
+// Diff compares two images pixel by pixel and returns an image of the differences and how many pixels differ.
+// Equal pixels are drawn faded out in gray and different ones in red. Images of different sizes differ in every pixel
+// that is only in one of them.
+func Diff(expected, actual image.Image, tolerance uint8) (*image.RGBA, int) {
+	bounds := expected.Bounds().Union(actual.Bounds())
+	diff := image.NewRGBA(bounds)
+	count := 0
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			p := image.Pt(x, y)
+			if !p.In(expected.Bounds()) || !p.In(actual.Bounds()) {
+				diff.Set(x, y, color.RGBA{R: 255, A: 255})
+				count++
+				continue
+			}
+
+			e := color.NRGBAModel.Convert(expected.At(x, y)).(color.NRGBA)
+			a := color.NRGBAModel.Convert(actual.At(x, y)).(color.NRGBA)
+			if channelDiff(e.R, a.R) > tolerance || channelDiff(e.G, a.G) > tolerance ||
+				channelDiff(e.B, a.B) > tolerance || channelDiff(e.A, a.A) > tolerance {
+				diff.Set(x, y, color.RGBA{R: 255, A: 255})
+				count++
+				continue
+			}
+
+			gray := color.GrayModel.Convert(e).(color.Gray)
+			faded := 128 + gray.Y/4
+			diff.Set(x, y, color.RGBA{R: faded, G: faded, B: faded, A: 255})
+		}
+	}
+	return diff, count
+}
+
+func channelDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+func readPNG(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return png.Decode(file)
+}
+
+func writePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
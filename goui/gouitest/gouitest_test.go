@@ -0,0 +1,196 @@
+package gouitest
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"../../goui"
+)
+
+func TestMain(m *testing.M) {
+	goui.FontDirectory("../../fonts")
+	os.Exit(m.Run())
+}
+
+func TestTextAlign(t *testing.T) {
+	text := goui.NewStyles().
+		Width(220).
+		Height(40).
+		Margin(goui.EdgeAll, 10).
+		Padding(goui.EdgeLeft, 8).
+		Padding(goui.EdgeRight, 8).
+		Background(color.White).
+		Color(color.Black)
+
+	Snapshot(t, "text-align", 240, 180, func(g goui.UI) {
+		g.Text("Left").Styles(text.TextAlign(goui.TextLeft))
+		g.Text("Center").Styles(text.TextAlign(goui.TextCenter))
+		g.Text("Right").Styles(text.TextAlign(goui.TextRight))
+	})
+}
+
+func TestCornerRadii(t *testing.T) {
+	rect := goui.NewStyles().
+		Width(80).
+		Height(50).
+		Margin(goui.EdgeAll, 10).
+		Background(color.RGBA{R: 57, G: 181, B: 74, A: 255})
+
+	Snapshot(t, "corner-radii", 300, 70, func(g goui.UI) {
+		g.Box(func() {
+			g.Box(func() {}).Styles(rect.BorderRadius(10))
+			g.Box(func() {}).Styles(rect.CornerRadius(goui.CornerTopLeft, 25).CornerRadius(goui.CornerBottomRight, 8))
+			g.Box(func() {}).Styles(rect.BorderRadius(25).Border(goui.EdgeAll, 3, color.White))
+		}).Styles(goui.NewStyles().FlexDirection(goui.Row))
+	})
+}
+
+func TestBorderStyles(t *testing.T) {
+	box := goui.NewStyles().
+		Width(80).
+		Height(50).
+		Margin(goui.EdgeAll, 10).
+		Background(color.White)
+	blue := color.RGBA{R: 33, G: 99, B: 230, A: 255}
+
+	Snapshot(t, "border-styles", 300, 140, func(g goui.UI) {
+		g.Box(func() {
+			g.Box(func() {}).Styles(box.Border(goui.EdgeAll, 4, blue).BorderStyle(goui.EdgeAll, goui.BorderDashed))
+			g.Box(func() {}).Styles(box.Border(goui.EdgeAll, 4, blue).BorderStyle(goui.EdgeAll, goui.BorderDotted))
+			g.Box(func() {}).Styles(box.Border(goui.EdgeAll, 4, blue).BorderStyle(goui.EdgeAll, goui.BorderDashed).BorderRadius(12))
+		}).Styles(goui.NewStyles().FlexDirection(goui.Row))
+		g.Box(func() {
+			g.Box(func() {}).Styles(box.
+				Border(goui.EdgeTop, 4, blue).
+				Border(goui.EdgeRight, 2, color.Black).BorderStyle(goui.EdgeRight, goui.BorderDotted).
+				Border(goui.EdgeBottom, 6, blue).BorderStyle(goui.EdgeBottom, goui.BorderDashed))
+			g.Box(func() {}).Styles(box.Border(goui.EdgeAll, 6, blue).BorderStyle(goui.EdgeAll, goui.BorderDotted).BorderRadius(25))
+		}).Styles(goui.NewStyles().FlexDirection(goui.Row))
+	})
+}
+
+func TestInsetShadows(t *testing.T) {
+	box := goui.NewStyles().
+		Width(80).
+		Height(50).
+		Margin(goui.EdgeAll, 10).
+		Background(color.White)
+	shadow := color.RGBA{A: 160}
+
+	Snapshot(t, "inset-shadows", 300, 70, func(g goui.UI) {
+		g.Box(func() {
+			g.Box(func() {}).Styles(box.InsetBoxShadow(0, 0, 8, 0, shadow))
+			g.Box(func() {}).Styles(box.InsetBoxShadow(4, 4, 4, 2, shadow).BorderRadius(12))
+			g.Box(func() {}).Styles(box.
+				InsetBoxShadow(0, 0, 6, 0, color.RGBA{R: 200, A: 200}).
+				BoxShadow(0, 2, 4, 0, shadow).
+				Border(goui.EdgeAll, 2, color.Black))
+		}).Styles(goui.NewStyles().FlexDirection(goui.Row))
+	})
+}
+
+func TestGradientStops(t *testing.T) {
+	box := goui.NewStyles().
+		Width(80).
+		Height(50).
+		Margin(goui.EdgeAll, 10)
+	red := color.RGBA{R: 230, G: 40, B: 40, A: 255}
+	yellow := color.RGBA{R: 240, G: 220, B: 40, A: 255}
+	blue := color.RGBA{R: 40, G: 90, B: 230, A: 255}
+
+	Snapshot(t, "gradient-stops", 300, 70, func(g goui.UI) {
+		g.Box(func() {
+			g.Box(func() {}).Styles(box.LinearGradient(90, goui.Stop(0, red), goui.Stop(0.5, yellow), goui.Stop(1, blue)))
+			g.Box(func() {}).Styles(box.
+				LinearGradient(180, goui.Stop(0, red), goui.Stop(0.2, yellow), goui.Stop(0.8, yellow), goui.Stop(1, blue)).
+				BorderRadius(10))
+			g.Box(func() {}).Styles(box.RadialGradient(goui.Stop(0, yellow), goui.Stop(0.4, red), goui.Stop(1, blue)))
+		}).Styles(goui.NewStyles().FlexDirection(goui.Row))
+	})
+}
+
+func TestDiff(t *testing.T) {
+	expected := image.NewRGBA(image.Rect(0, 0, 3, 1))
+	actual := image.NewRGBA(image.Rect(0, 0, 4, 1))
+	expected.Set(1, 0, color.RGBA{R: 100, A: 255})
+	actual.Set(1, 0, color.RGBA{R: 102, A: 255})
+	expected.Set(2, 0, color.RGBA{G: 100, A: 255})
+	actual.Set(2, 0, color.RGBA{G: 110, A: 255})
+
+	diff, count := Diff(expected, actual, 2)
+	if count != 2 {
+		t.Errorf("%v pixels differ, expected 2", count)
+	}
+	red := color.RGBA{R: 255, A: 255}
+	for x, differs := range []bool{false, false, true, true} {
+		if got := diff.RGBAAt(x, 0); (got == red) != differs {
+			t.Errorf("pixel %v of the diff is %v", x, got)
+		}
+	}
+
+	if _, count := Diff(expected, actual, 10); count != 1 {
+		t.Errorf("%v pixels differ with a tolerance of 10, expected only the missing one", count)
+	}
+}
+
+// recorder records the failures of CompareImage instead of failing the test
+type recorder struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recorder) Helper()                                   {}
+func (r *recorder) Errorf(format string, args ...interface{}) { r.failed = true }
+func (r *recorder) Fatalf(format string, args ...interface{}) { r.failed = true }
+
+func TestCompareImage(t *testing.T) {
+	dir := Dir
+	Dir = t.TempDir()
+	defer func() { Dir = dir }()
+
+	black := color.RGBA{A: 255}
+	golden := filled(2, 2, black)
+	if err := writePNG(filepath.Join(Dir, "square.png"), golden); err != nil {
+		t.Fatal(err)
+	}
+	diffPath := filepath.Join(Dir, "square.diff.png")
+
+	similar := filled(2, 2, black)
+	similar.Set(0, 0, color.RGBA{R: Tolerance, G: Tolerance, B: Tolerance, A: 255})
+	r := &recorder{TB: t}
+	CompareImage(r, "square", similar)
+	if r.failed {
+		t.Error("an image within the tolerance failed")
+	}
+	if _, err := os.Stat(diffPath); err == nil {
+		t.Error("a diff was written for an image within the tolerance")
+	}
+
+	different := filled(2, 2, black)
+	different.Set(1, 1, color.White)
+	r = &recorder{TB: t}
+	CompareImage(r, "square", different)
+	if !r.failed {
+		t.Error("a different image passed")
+	}
+	diff, err := readPNG(diffPath)
+	if err != nil {
+		t.Fatalf("no diff was written: %v", err)
+	}
+	if got := color.RGBAModel.Convert(diff.At(1, 1)); got != (color.RGBA{R: 255, A: 255}) {
+		t.Errorf("the different pixel is %v in the diff", got)
+	}
+}
+
+func filled(width, height int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
@@ -0,0 +1,136 @@
+package goui
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/shibukawa/nanovgo"
+)
+
+// GradientStop is a color at a position along a gradient, from 0 at the start to 1 at the end
+type GradientStop struct {
+	Offset float64
+	Color  color.Color
+}
+
+// Stop returns a gradient stop, to shorten LinearGradient(90, goui.Stop(0, red), goui.Stop(1, blue))
+func Stop(offset float64, color color.Color) GradientStop {
+	return GradientStop{Offset: offset, Color: color}
+}
+
+type backgroundKind int
+
+const (
+	backgroundNone backgroundKind = iota
+	backgroundLinear
+	backgroundRadial
+	backgroundBox
+	backgroundImage
+)
+
+// backgroundPaint is drawn on top of the background color
+type backgroundPaint struct {
+	kind    backgroundKind
+	angle   float64 // degrees, for linear gradients
+	feather float64 // for box gradients
+	stops   []GradientStop
+	path    string
+	res     resource // the image, looked up while the styles are applied
+}
+
+// withResource returns the paint of an image background with the image looked up in the resources
+func (p *backgroundPaint) withResource(g *gui) *backgroundPaint {
+	if p == nil || p.kind != backgroundImage {
+		return p
+	}
+	withRes := *p
+	withRes.res = g.resources.image(g.ctx, p.path)
+	return &withRes
+}
+
+// drawBackgroundPaint fills the rounded rectangle with the gradient or image of the styles.
+// nanovgo gradients only have two colors, gradients with more stops are drawn as several layers
+// that fade in from transparent, which mixes like a single gradient as long as the colors are opaque.
+func drawBackgroundPaint(ctx canvas, x, y, w, h float32, r [4]float32, p *backgroundPaint) {
+	if p == nil || w <= 0 || h <= 0 {
+		return
+	}
+
+	fill := func(paint paint) {
+		ctx.BeginPath()
+		ctx.SetFillPaint(paint)
+		roundedRect(ctx, x, y, w, h, r)
+		ctx.Fill()
+	}
+
+	switch p.kind {
+	case backgroundImage:
+		if p.res.state != resourceLoaded || p.res.width == 0 || p.res.height == 0 {
+			return
+		}
+		// cover the box, keeping the aspect ratio
+		imgWidth, imgHeight := float32(p.res.width), float32(p.res.height)
+		scale := maxF(w/imgWidth, h/imgHeight)
+		imgWidth *= scale
+		imgHeight *= scale
+		fill(imagePattern(x+(w-imgWidth)/2, y+(h-imgHeight)/2, imgWidth, imgHeight, p.res.image, 1))
+
+	case backgroundBox:
+		if len(p.stops) < 2 {
+			return
+		}
+		// the outer color is reached at the edge of the box
+		feather := float32(p.feather)
+		inner, outer := colorToNanoColor(p.stops[0].Color), colorToNanoColor(p.stops[1].Color)
+		fill(boxGradient(x+feather/2, y+feather/2, w-feather, h-feather, maxF(0, maxRadius(r)-feather/2), feather, inner, outer))
+
+	case backgroundLinear, backgroundRadial:
+		if len(p.stops) == 0 {
+			return
+		}
+		if len(p.stops) == 1 {
+			ctx.BeginPath()
+			ctx.SetFillColor(colorToNanoColor(p.stops[0].Color))
+			roundedRect(ctx, x, y, w, h, r)
+			ctx.Fill()
+			return
+		}
+
+		gradient := linearGradientBetween(x, y, w, h, p.angle)
+		if p.kind == backgroundRadial {
+			gradient = radialGradientBetween(x, y, w, h)
+		}
+		for i := 0; i+1 < len(p.stops); i++ {
+			start, end := p.stops[i], p.stops[i+1]
+			from, to := colorToNanoColor(start.Color), colorToNanoColor(end.Color)
+			if i > 0 {
+				from = transparent(to)
+			}
+			// hard stops at the same offset still need a direction
+			fill(gradient(float32(start.Offset), float32(math.Max(end.Offset, start.Offset+1e-3)), from, to))
+		}
+	}
+}
+
+// linearGradientBetween returns the paints between two offsets of a linear gradient. Like in css the angle
+// is in degrees clockwise from the top, and the gradient line is long enough to reach the corners.
+func linearGradientBetween(x, y, w, h float32, angle float64) func(from, to float32, inner, outer nanovgo.Color) paint {
+	rad := angle * math.Pi / 180
+	dirX, dirY := float32(math.Sin(rad)), float32(-math.Cos(rad))
+	length := float32(math.Abs(float64(w)*math.Sin(rad)) + math.Abs(float64(h)*math.Cos(rad)))
+	startX, startY := x+w/2-dirX*length/2, y+h/2-dirY*length/2
+
+	return func(from, to float32, inner, outer nanovgo.Color) paint {
+		return linearGradient(startX+dirX*length*from, startY+dirY*length*from,
+			startX+dirX*length*to, startY+dirY*length*to, inner, outer)
+	}
+}
+
+// radialGradientBetween returns the paints between two offsets of a circular gradient in the middle of the box,
+// which ends at the corners
+func radialGradientBetween(x, y, w, h float32) func(from, to float32, inner, outer nanovgo.Color) paint {
+	radius := float32(math.Hypot(float64(w), float64(h)) / 2)
+	return func(from, to float32, inner, outer nanovgo.Color) paint {
+		return radialGradient(x+w/2, y+h/2, radius*from, radius*to, inner, outer)
+	}
+}
@@ -1,8 +1,16 @@
 package goui
 
 type Handle struct {
-	styles  Styles
-	onClick func(ev ClickEvent)
+	styles         Styles
+	key            string
+	onClick        func(ev ClickEvent)
+	onKey          func(ev KeyEvent)
+	scrollIntoView bool
+	disabled       bool
+	focusable      bool
+	tabIndex       int
+	animations     []namedAnimation
+	classes        []string
 }
 
 func (h *Handle) Styles(styles ...Styles) *Handle {
@@ -12,8 +20,68 @@ func (h *Handle) Styles(styles ...Styles) *Handle {
 	return h
 }
 
+// Class applies the named styles of the stylesheets given to UI.Stylesheet, in order.
+// Styles given to Handle.Styles take precedence over them.
+func (h *Handle) Class(names ...string) *Handle {
+	h.classes = append(h.classes, names...)
+	return h
+}
+
+// Key identifies the widget between renders, which is needed for it to keep its state (like the scroll offset)
+// when widgets are added, removed or reordered. It only has to be unique among its siblings.
+// Without a key the widget is identified by where in the code it was created.
+func (h *Handle) Key(key string) *Handle {
+	h.key = key
+	return h
+}
+
+// Click is called when the widget, or one of its children, is clicked.
+// Use ClickEvent.StopPropagation to prevent the parent widgets from receiving the event.
 func (h *Handle) Click(callback func(ev ClickEvent)) *Handle {
 	h.onClick = callback
 	return h
+}
+
+// ScrollIntoView scrolls all scrolling boxes containing the widget so that it becomes visible.
+// The scrolling happens every frame this is called.
+func (h *Handle) ScrollIntoView() *Handle {
+	h.scrollIntoView = true
+	return h
+}
 
+// Disabled stops the widget and its children from being clicked and focused
+func (h *Handle) Disabled(disabled bool) *Handle {
+	h.disabled = disabled
+	return h
+}
+
+// Focusable lets the widget receive the keyboard focus, inputs and buttons are focusable by default
+func (h *Handle) Focusable() *Handle {
+	h.focusable = true
+	return h
+}
+
+// TabIndex changes the order in which tab moves the focus, like the html tabindex attribute.
+// Widgets with a positive index are visited first in increasing order, followed by the ones with index 0
+// in document order. A negative index makes the widget focusable but skips it when tabbing.
+func (h *Handle) TabIndex(index int) *Handle {
+	h.focusable = true
+	h.tabIndex = index
+	return h
+}
+
+// OnKey is called for key events while the widget, or one of its children, has the keyboard focus.
+// Use KeyEvent.StopPropagation to prevent the parent widgets from receiving the event.
+func (h *Handle) OnKey(callback func(ev KeyEvent)) *Handle {
+	h.onKey = callback
+	return h
+}
+
+// Animate runs a keyframe animation on the widget. It starts the first time the widget is rendered with
+// an animation of that name, and runs for as long as it is rendered with it. Render it without the animation
+// and with it again to restart it, or change the name.
+func (h *Handle) Animate(name string, animation Animation) *Handle {
+	animation.Keyframes = sortKeyframes(animation.Keyframes)
+	h.animations = append(h.animations, namedAnimation{name, animation})
+	return h
 }
@@ -0,0 +1,88 @@
+package goui
+
+// updateBounds stores the absolute position and size of every widget after the layout has been calculated
+func updateBounds(w *widgetContainer, parentX, parentY float32) {
+	w.x = parentX + w.layout.LayoutGetLeft()
+	w.y = parentY + w.layout.LayoutGetTop()
+	w.width = w.layout.LayoutGetWidth()
+	w.height = w.layout.LayoutGetHeight()
+
+	w.alpha, w.transform = 1, identityTransform
+	if w.parent != nil {
+		w.alpha, w.transform = w.parent.alpha, w.parent.transform
+	}
+	if w.handle.styles.opacity != unset {
+		w.alpha *= float32(w.handle.styles.opacity)
+	}
+	w.transform = w.transform.multiply(w.localTransform())
+	w.inverse = w.transform.inverse()
+
+	if box, ok := w.widget.(*boxWidget); ok {
+		childX, childY := w.x, w.y
+		if box.scroll != nil {
+			childX -= box.scroll.x
+			childY -= box.scroll.y
+		}
+		for _, child := range box.children {
+			updateBounds(child, childX, childY)
+		}
+	}
+}
+
+// hitTest returns the deepest widget under (x, y), or nil if there is none.
+// Children are checked in reverse order since widgets added later are drawn on top.
+func hitTest(w *widgetContainer, x, y float32) *widgetContainer {
+	// children are not visible outside of boxes that clip them
+	if clipsChildren(w.handle.styles) && !w.contains(x, y) {
+		return nil
+	}
+
+	if box, ok := w.widget.(*boxWidget); ok {
+		for i := len(box.children) - 1; i >= 0; i-- {
+			if hit := hitTest(box.children[i], x, y); hit != nil {
+				return hit
+			}
+		}
+	}
+
+	if w.contains(x, y) {
+		return w
+	}
+	return nil
+}
+
+// contains returns if the point in the window is inside the widget, after undoing its transformation
+func (w *widgetContainer) contains(x, y float32) bool {
+	x, y = w.inverse.apply(x, y)
+	return x >= w.x && x < w.x+w.width && y >= w.y && y < w.y+w.height
+}
+
+// dispatchClick calls the click handler of the target widget and then bubbles the event up to its
+// ancestors until a handler calls ClickEvent.StopPropagation. Disabled widgets swallow the click.
+// The global OnClick callback is called last, unless the propagation was stopped.
+func (g *gui) dispatchClick(target *widgetContainer, ev ClickEvent) bool {
+	if ev.stopped == nil {
+		ev.stopped = new(bool)
+	}
+	handled := false
+
+	for w := target; w != nil; w = w.parent {
+		if w.handle.disabled {
+			return handled
+		}
+		if w.handle.onClick == nil {
+			continue
+		}
+		w.handle.onClick(ev)
+		handled = true
+		if *ev.stopped {
+			return handled
+		}
+	}
+
+	if g.clickCb != nil {
+		g.clickCb(ev)
+		handled = true
+	}
+	return handled
+}
@@ -0,0 +1,84 @@
+package goui
+
+// Inherited are the text styles that widgets take from their parent box when they don't set them, like in css
+type Inherited int
+
+const (
+	InheritedColor Inherited = 1 << iota
+	InheritedFontFamily
+	InheritedFontSize
+	InheritedTextAlign
+
+	InheritedAll = InheritedColor | InheritedFontFamily | InheritedFontSize | InheritedTextAlign
+)
+
+func (value Inherited) String() string {
+	names := []string{"color", "font-family", "font-size", "text-align"}
+	str := ""
+	for i, name := range names {
+		if value&(1<<uint(i)) == 0 {
+			continue
+		}
+		if str != "" {
+			str += "|"
+		}
+		str += name
+	}
+	if str == "" {
+		return "none"
+	}
+	return str
+}
+
+// reset unsets the inherited styles, so that they are not combined from other styles
+func (h Styles) reset(styles Inherited) Styles {
+	if styles&InheritedColor != 0 {
+		h.color = nil
+	}
+	if styles&InheritedFontFamily != 0 {
+		h.fontFamily = ""
+	}
+	if styles&InheritedFontSize != 0 {
+		h.fontSize = unset
+	}
+	if styles&InheritedTextAlign != 0 {
+		h.textAlign = unset
+	}
+	return h
+}
+
+// setInherited returns the inherited styles that have a value
+func (h Styles) setInherited() Inherited {
+	var styles Inherited
+	if h.color != nil {
+		styles |= InheritedColor
+	}
+	if h.fontFamily != "" {
+		styles |= InheritedFontFamily
+	}
+	if h.fontSize != unset {
+		styles |= InheritedFontSize
+	}
+	if h.textAlign != unset {
+		styles |= InheritedTextAlign
+	}
+	return styles
+}
+
+// inherit sets the inherited styles that are unset to the ones of the parent, which were already inherited
+// from its parent, so every widget is only visited once. Styles that were reset with Initial stay unset.
+func (h Styles) inherit(parent Styles) Styles {
+	if h.color == nil && h.initial&InheritedColor == 0 {
+		h.color = parent.color
+	}
+	if h.fontFamily == "" && h.initial&InheritedFontFamily == 0 {
+		h.fontFamily = parent.fontFamily
+	}
+	if h.fontSize == unset && h.initial&InheritedFontSize == 0 {
+		h.fontSize = parent.fontSize
+	}
+	if h.textAlign == unset && h.initial&InheritedTextAlign == 0 {
+		h.textAlign = parent.textAlign
+	}
+	return h
+}
@@ -0,0 +1,71 @@
+package goui
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestDriverInherit(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	blue := color.RGBA{B: 255, A: 255}
+	d := newTestDriver(t, 300, 300, func(g UI) {
+		g.Box(func() {
+			g.Box(func() {
+				g.Text("a").Key("a")
+				g.Text("b").Key("b").Styles(NewStyles().Initial(InheritedColor))
+				g.Text("c").Key("c").Styles(NewStyles().Initial(InheritedColor).Color(blue))
+				g.Button("d").Key("d")
+			}).Key("inner").Styles(NewStyles().TextAlign(TextRight))
+			g.Box(func() {
+				g.Text("e").Key("e")
+			}).Key("reset").Styles(NewStyles().Initial(InheritedAll).FontSize(12))
+		}).Key("outer").Styles(NewStyles().Color(red).FontSize(30).FontFamily(FontBold))
+	})
+
+	tests := []struct {
+		key        string
+		color      color.Color
+		fontSize   float64
+		fontFamily string
+		textAlign  TextAlign
+	}{
+		{"inner", red, 30, FontBold, TextRight},
+		{"a", red, 30, FontBold, TextRight},
+		{"b", nil, 30, FontBold, TextRight},
+		{"c", blue, 30, FontBold, TextRight},
+		{"reset", nil, 12, "", unset},
+		{"e", nil, 12, "", unset},
+	}
+	for _, test := range tests {
+		s := find(t, d, test.key).Styles
+		if s.color != test.color || s.fontSize != test.fontSize || s.fontFamily != test.fontFamily || s.textAlign != test.textAlign {
+			t.Errorf("%v has color %v, font size %v, font %q and text align %v, expected %v, %v, %q and %v", test.key,
+				s.color, s.fontSize, s.fontFamily, s.textAlign, test.color, test.fontSize, test.fontFamily, test.textAlign)
+		}
+	}
+
+	// buttons have their own alignment, but take the font
+	if s := find(t, d, "d").Styles; s.fontSize != 30 || s.fontFamily != FontBold || s.textAlign != TextCenter {
+		t.Errorf("the button has font size %v, font %q and text align %v", s.fontSize, s.fontFamily, s.textAlign)
+	}
+}
+
+func TestCombineInitial(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	tests := []struct {
+		name    string
+		styles  Styles
+		color   color.Color
+		initial Inherited
+	}{
+		{"initial replaces the color", CombineStyles(NewStyles().Color(red), NewStyles().Initial(InheritedColor)), nil, InheritedColor},
+		{"the color replaces initial", CombineStyles(NewStyles().Initial(InheritedColor), NewStyles().Color(red)), red, 0},
+		{"setting the color again", NewStyles().Initial(InheritedAll).Color(red), red, InheritedAll &^ InheritedColor},
+	}
+	for _, test := range tests {
+		if test.styles.color != test.color || test.styles.initial != test.initial {
+			t.Errorf("%v: color %v and initial %v, expected %v and %v",
+				test.name, test.styles.color, test.styles.initial, test.color, test.initial)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package goui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/kjk/flex"
+	"github.com/shibukawa/nanovgo"
+)
+
+const (
+	caretBlinkInterval = 500 * time.Millisecond
+	defaultInputWidth  = 200
+)
+
+var inputStyles = NewStyles().
+	Background(Token("surface.subtle")).
+	Padding(EdgeAll, 6).
+	BorderRadius(3)
+
+// InputState holds the text and editing state of an Input, it has to be kept between renders
+type InputState struct {
+	Text        string
+	Placeholder string
+	Focused     bool
+	CursorPos   int // position of the caret in runes
+	OnChange    func(text string)
+
+	Managed bool
+
+	selecting  bool // the text between anchor and CursorPos is selected
+	anchor     int
+	scrollX    float32
+	blinkStart time.Time
+}
+
+func (i InputState) String() string {
+	return fmt.Sprintf("InputState: (%v)", i.Text)
+}
+
+// Selection returns the start and end of the selected text in runes, they are equal when nothing is selected
+func (i *InputState) Selection() (int, int) {
+	if !i.selecting {
+		return i.CursorPos, i.CursorPos
+	}
+	if i.anchor < i.CursorPos {
+		return i.anchor, i.CursorPos
+	}
+	return i.CursorPos, i.anchor
+}
+
+// moveCursor moves the caret, extending the selection from the current position if selecting is true
+func (i *InputState) moveCursor(pos int, selecting bool) {
+	pos = clampInt(pos, 0, len([]rune(i.Text)))
+	if selecting && !i.selecting {
+		i.anchor = i.CursorPos
+	}
+	i.selecting = selecting && pos != i.anchor
+	i.CursorPos = pos
+}
+
+// replaceSelection replaces the selected text, or inserts the text at the caret if nothing is selected
+func (i *InputState) replaceSelection(text string) {
+	runes := []rune(i.Text)
+	start, end := i.Selection()
+	start, end = clampInt(start, 0, len(runes)), clampInt(end, 0, len(runes))
+	if start == end && text == "" {
+		return
+	}
+
+	i.Text = string(runes[:start]) + text + string(runes[end:])
+	i.selecting = false
+	i.moveCursor(start+len([]rune(text)), false)
+
+	if i.OnChange != nil {
+		i.OnChange(i.Text)
+	}
+}
+
+func (i *InputState) focus(focused bool) {
+	i.Focused = focused
+	i.selecting = false
+}
+
+// restartBlink shows the caret for a full interval, after it was moved or the input was focused
+func (i *InputState) restartBlink(now time.Time) {
+	i.blinkStart = now
+}
+
+// caretVisible returns if the blinking caret is shown at the given time
+func (i *InputState) caretVisible(now time.Time) bool {
+	return i.Focused && now.Sub(i.blinkStart)%(2*caretBlinkInterval) < caretBlinkInterval
+}
+
+func clampInt(val, min, max int) int {
+	if val < min {
+		return min
+	}
+	if val > max {
+		return max
+	}
+	return val
+}
+
+func (g *gui) Input(state *InputState) *Handle {
+	// the focused input is found again on every render, so an input that is no longer rendered loses the focus
+	if state.Focused {
+		if g.focusedInput != nil && g.focusedInput != state {
+			g.focusedInput.focus(false)
+		}
+		g.focusedInput = state
+		if state.blinkStart.IsZero() {
+			state.restartBlink(g.frameTime)
+		}
+	}
+	return g.addWidget(&inputWidget{state: state, frameTime: g.frameTime})
+}
+
+type inputWidget struct {
+	state     *InputState
+	frameTime time.Time // time of the frame it is rendered in, for the blinking caret
+}
+
+func (w *inputWidget) defaultStyles() Styles {
+	return inputStyles
+}
+
+func (w *inputWidget) render(ctx canvas, parentX, parentY float32, l *flex.Node, s Styles) {
+	drawRect(ctx, parentX, parentY, l, s)
+
+	state := w.state
+	x, y, width, height := contentBox(parentX, parentY, l)
+	runes := []rune(state.Text)
+	state.CursorPos = clampInt(state.CursorPos, 0, len(runes))
+
+	setFont(ctx, s)
+	ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignMiddle)
+	textWidth, _ := ctx.TextBounds(0, 0, state.Text)
+	caretX, _ := ctx.TextBounds(0, 0, string(runes[:state.CursorPos]))
+
+	// scroll horizontally to keep the caret visible
+	if caretX-state.scrollX > width {
+		state.scrollX = caretX - width
+	}
+	if caretX-state.scrollX < 0 {
+		state.scrollX = caretX
+	}
+	state.scrollX = maxF(0, minF(state.scrollX, textWidth-width+1))
+
+	ctx.Save()
+	ctx.IntersectScissor(x, y, width, height)
+
+	textX := x - state.scrollX
+	textY := y + height/2
+
+	if start, end := state.Selection(); start != end && state.Focused {
+		startX, _ := ctx.TextBounds(0, 0, string(runes[:start]))
+		endX, _ := ctx.TextBounds(0, 0, string(runes[:end]))
+		ctx.BeginPath()
+		ctx.SetFillColor(colorToNanoColor(s.theme.color("selection")))
+		ctx.Rect(textX+startX, y, endX-startX, height)
+		ctx.Fill()
+	}
+
+	if state.Text == "" {
+		ctx.SetFillColor(colorToNanoColor(s.theme.color("text.placeholder")))
+		ctx.Text(textX, textY, state.Placeholder)
+	} else {
+		col := s.color
+		if col == nil {
+			col = s.theme.color("text.primary")
+		}
+		ctx.SetFillColor(colorToNanoColor(col))
+		ctx.Text(textX, textY, state.Text)
+	}
+
+	if state.caretVisible(w.frameTime) {
+		col := s.color
+		if col == nil {
+			col = s.theme.color("text.primary")
+		}
+		ctx.BeginPath()
+		ctx.SetFillColor(colorToNanoColor(col))
+		ctx.Rect(textX+caretX, y, 1, height)
+		ctx.Fill()
+	}
+
+	ctx.Restore()
+}
+
+// caretAt returns the caret position closest to x, relative to the start of the text
+func (w *inputWidget) caretAt(ctx canvas, s Styles, x float32) int {
+	setFont(ctx, s)
+	ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignMiddle)
+	for i, glyph := range ctx.TextGlyphPositions(0, 0, w.state.Text) {
+		if x < (glyph.MinX+glyph.MaxX)/2 {
+			return i
+		}
+	}
+	return len([]rune(w.state.Text))
+}
+
+// inputMeasureFunc gives inputs a default width and the height of a line of text
+func inputMeasureFunc(ctx canvas, s Styles) flex.MeasureFunc {
+	return func(node *flex.Node, width float32, widthMode flex.MeasureMode, height float32, heightMode flex.MeasureMode) flex.Size {
+		setFont(ctx, s)
+		_, _, lineHeight := ctx.TextMetrics()
+		return flex.Size{
+			Width:  constrain(defaultInputWidth, width, widthMode),
+			Height: constrain(lineHeight, height, heightMode),
+		}
+	}
+}
+
+// placeCaret moves the caret of a clicked input to the clicked position
+func (g *gui) placeCaret(target *widgetContainer, ev ClickEvent) {
+	if target == nil {
+		return
+	}
+	input, ok := target.widget.(*inputWidget)
+	if !ok || !input.state.Focused {
+		return
+	}
+
+	textX := target.x + inset(target.layout, flex.EdgeLeft) - input.state.scrollX
+	x, _ := target.inverse.apply(float32(ev.X), float32(ev.Y))
+	pos := input.caretAt(g.ctx, target.handle.styles, x-textX)
+	input.state.moveCursor(pos, ev.Shift)
+	input.state.restartBlink(g.clock())
+}
+
+// handleInputKey edits the focused input
+func (g *gui) handleInputKey(ev KeyEvent) bool {
+	state := g.focusedInput
+	if state == nil || ev.Action == Release {
+		return false
+	}
+
+	length := len([]rune(state.Text))
+	start, end := state.Selection()
+
+	switch ev.Key {
+	case glfw.KeyLeft:
+		if start != end && !ev.Shift {
+			state.moveCursor(start, false)
+		} else {
+			state.moveCursor(state.CursorPos-1, ev.Shift)
+		}
+	case glfw.KeyRight:
+		if start != end && !ev.Shift {
+			state.moveCursor(end, false)
+		} else {
+			state.moveCursor(state.CursorPos+1, ev.Shift)
+		}
+	case glfw.KeyHome:
+		state.moveCursor(0, ev.Shift)
+	case glfw.KeyEnd:
+		state.moveCursor(length, ev.Shift)
+	case glfw.KeyBackspace:
+		if start == end {
+			state.moveCursor(state.CursorPos-1, true)
+		}
+		state.replaceSelection("")
+	case glfw.KeyDelete:
+		if start == end {
+			state.moveCursor(state.CursorPos+1, true)
+		}
+		state.replaceSelection("")
+	case glfw.KeyA:
+		if !ev.Ctrl {
+			return false
+		}
+		state.moveCursor(0, false)
+		state.moveCursor(length, true)
+	default:
+		return false
+	}
+	state.restartBlink(g.clock())
+	return true
+}
+
+// handleInputText inserts typed text into the focused input
+func (g *gui) handleInputText(char rune) bool {
+	if g.focusedInput == nil {
+		return false
+	}
+	g.focusedInput.replaceSelection(string(char))
+	g.focusedInput.restartBlink(g.clock())
+	return true
+}
+
+// scheduleCaretBlink returns when the next frame has to be rendered for the caret to blink, or the zero time
+func (g *gui) scheduleCaretBlink() time.Time {
+	if g.focusedInput == nil {
+		return time.Time{}
+	}
+	now := g.clock()
+	elapsed := now.Sub(g.focusedInput.blinkStart)
+	return now.Add(caretBlinkInterval - elapsed%caretBlinkInterval)
+}
@@ -0,0 +1,11 @@
+package goui
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	FontDirectory("../fonts")
+	os.Exit(m.Run())
+}
@@ -0,0 +1,106 @@
+package goui
+
+// widgetState is the interaction state of a widget, used to pick its styles
+type widgetState int
+
+const (
+	stateHover widgetState = 1 << iota
+	stateActive
+	stateFocus
+	stateDisabled
+)
+
+// path returns the ids of the widget and all of its ancestors
+func path(w *widgetContainer) map[string]bool {
+	ids := map[string]bool{}
+	for ; w != nil; w = w.parent {
+		ids[w.id] = true
+	}
+	return ids
+}
+
+// updateHover finds the widgets under the cursor, like css :hover they include all the ancestors
+func (g *gui) updateHover() bool {
+	if g.root == nil {
+		return false
+	}
+	hovered := path(hitTest(g.root, g.mouseX, g.mouseY))
+
+	changed := len(hovered) != len(g.hovered)
+	for id := range hovered {
+		if !g.hovered[id] {
+			changed = true
+		}
+	}
+	g.hovered = hovered
+	return changed
+}
+
+// handlePress is called when a mouse button is pressed
+func (g *gui) handlePress(ev ClickEvent) bool {
+	if g.startScrollDrag(ev) || g.root == nil {
+		return true
+	}
+
+	target := hitTest(g.root, float32(ev.X), float32(ev.Y))
+	g.pressed = path(target)
+	g.pressButton = ev.Button
+
+	if ev.Button == MouseButtonLeft {
+		g.focusAt(target)
+		g.placeCaret(target, ev)
+	}
+	return true
+}
+
+// handleRelease is called when a mouse button is released. The click is sent to the deepest widget
+// that both the press and the release were on, like a click in the browser.
+func (g *gui) handleRelease(ev ClickEvent) bool {
+	pressed := g.pressed
+	g.pressed = nil
+	g.drag = nil
+
+	if pressed == nil || ev.Button != g.pressButton || g.root == nil {
+		return pressed != nil
+	}
+
+	target := hitTest(g.root, float32(ev.X), float32(ev.Y))
+	for target != nil && !pressed[target.id] {
+		target = target.parent
+	}
+	g.dispatchClick(target, ev)
+	return true
+}
+
+// handleMouseMove is called when the cursor moves
+func (g *gui) handleMouseMove(ev MouseMoveEvent) bool {
+	g.mouseX, g.mouseY = float32(ev.X), float32(ev.Y)
+	if g.drag != nil {
+		g.updateScrollDrag(g.mouseX, g.mouseY)
+		return true
+	}
+	return g.updateHover()
+}
+
+// updateWidgetStates sets the interaction state of every widget in a new widget tree
+func (g *gui) updateWidgetStates() {
+	g.root.walk(func(w *widgetContainer) {
+		w.state = 0
+		if w.handle.disabled || (w.parent != nil && w.parent.state&stateDisabled != 0) {
+			w.state |= stateDisabled
+			return
+		}
+		if g.hovered[w.id] {
+			w.state |= stateHover
+		}
+		if g.pressed[w.id] && g.pressButton == MouseButtonLeft {
+			w.state |= stateActive
+		}
+		if w.id == g.focusID {
+			w.state |= stateFocus
+			// like css :focus-visible, the ring is only shown for keyboard focus and on inputs
+			_, isInput := w.widget.(*inputWidget)
+			w.focusRing = g.focusVisible || isInput
+		}
+	})
+}
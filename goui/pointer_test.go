@@ -0,0 +1,96 @@
+package goui
+
+import "testing"
+
+func TestClickPressAndReleaseOnDifferentWidgets(t *testing.T) {
+	clicks := map[string]int{}
+	global := 0
+	d := newTestDriver(t, 200, 200, func(g UI) {
+		g.OnClick(func(ev ClickEvent) { global++ })
+		for _, key := range []string{"a", "b"} {
+			key := key
+			g.Button(key).Key(key).
+				Styles(NewStyles().Width(100).Height(40)).
+				Click(func(ev ClickEvent) { clicks[key]++ })
+		}
+	})
+
+	ax, ay := find(t, d, "a").Center()
+	bx, by := find(t, d, "b").Center()
+	d.MoveMouse(ax, ay)
+	d.Press(ClickEvent{X: ax, Y: ay})
+	d.Step()
+	if global != 0 {
+		t.Errorf("the global click callback was called on press")
+	}
+
+	d.MoveMouse(bx, by)
+	d.Release(ClickEvent{X: bx, Y: by})
+	d.Step()
+	if clicks["a"] != 0 || clicks["b"] != 0 {
+		t.Errorf("a click was sent to a widget that only got the press or the release: %v", clicks)
+	}
+	if global != 1 {
+		t.Errorf("the global click callback was called %v times on release, want 1", global)
+	}
+}
+
+func TestClickGlobalAfterWidgets(t *testing.T) {
+	var calls []string
+	stop := false
+	d := newTestDriver(t, 200, 200, func(g UI) {
+		g.OnClick(func(ev ClickEvent) { calls = append(calls, "global") })
+		g.Button("a").Key("a").
+			Styles(NewStyles().Width(100).Height(40)).
+			Click(func(ev ClickEvent) {
+				calls = append(calls, "a")
+				if stop {
+					ev.StopPropagation()
+				}
+			})
+	})
+
+	x, y := find(t, d, "a").Center()
+	d.Click(ClickEvent{X: x, Y: y})
+	d.Step()
+	if len(calls) != 2 || calls[0] != "a" || calls[1] != "global" {
+		t.Errorf("calls %v, want [a global]", calls)
+	}
+
+	calls, stop = nil, true
+	d.Click(ClickEvent{X: x, Y: y})
+	d.Step()
+	if len(calls) != 1 || calls[0] != "a" {
+		t.Errorf("calls %v after stopping the propagation, want [a]", calls)
+	}
+}
+
+func TestClickTransformedWidget(t *testing.T) {
+	tests := []struct {
+		name    string
+		styles  Styles
+		x, y    float64
+		clicked bool
+	}{
+		{"rotated, inside", NewStyles().Rotate(90), 100, 65, true},
+		{"rotated, only inside before rotating", NewStyles().Rotate(90), 60, 100, false},
+		{"scaled, inside", NewStyles().Scale(0.5, 0.5), 110, 100, true},
+		{"scaled, only inside before scaling", NewStyles().Scale(0.5, 0.5), 60, 100, false},
+		{"translated, inside", NewStyles().Translate(0, 50), 100, 150, true},
+		{"translated, only inside before moving", NewStyles().Translate(0, 50), 100, 100, false},
+	}
+	for _, test := range tests {
+		clicked := false
+		// the button is 100 by 40 with its middle at 100, 100
+		d := newTestDriver(t, 200, 200, func(g UI) {
+			g.Button("transformed").
+				Styles(NewStyles().Width(100).Height(40).Margin(EdgeLeft, 50).Margin(EdgeTop, 80), test.styles).
+				Click(func(ev ClickEvent) { clicked = true })
+		})
+		d.Click(ClickEvent{X: test.x, Y: test.y})
+		d.Step()
+		if clicked != test.clicked {
+			t.Errorf("%v: clicked %v, expected %v", test.name, clicked, test.clicked)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package goui
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// reloadInterval is how often the files are checked for changes in Debug mode
+var reloadInterval = 500 * time.Millisecond
+
+// fileWatcher polls the modification times of files, so that stylesheets, fonts and images can be
+// reloaded while developing without restarting the program
+type fileWatcher struct {
+	lock  sync.Mutex
+	files map[string]*watchedFile
+}
+
+type watchedFile struct {
+	modTime  time.Time
+	onChange func()
+}
+
+func newFileWatcher() *fileWatcher {
+	return &fileWatcher{files: map[string]*watchedFile{}}
+}
+
+// watch calls onChange from poll when the file changes. Watching a file again keeps the first callback.
+func (w *fileWatcher) watch(path string, onChange func()) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if _, ok := w.files[path]; ok {
+		return
+	}
+	file := &watchedFile{onChange: onChange}
+	if info, err := os.Stat(path); err == nil {
+		file.modTime = info.ModTime()
+	}
+	w.files[path] = file
+}
+
+// poll returns the callbacks of the files that changed since the last poll
+func (w *fileWatcher) poll() []func() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var changed []func()
+	for path, file := range w.files {
+		info, err := os.Stat(path)
+		if err != nil || info.ModTime().Equal(file.modTime) {
+			continue
+		}
+		file.modTime = info.ModTime()
+		changed = append(changed, file.onChange)
+	}
+	return changed
+}
+
+// startReloading watches the fonts, and the images and stylesheets as they are used
+func (g *gui) startReloading() {
+	g.watcher = newFileWatcher()
+	g.resources.onCreate = g.watchImage
+	ctx := newReloadedFonts(g.ctx)
+	g.ctx = ctx
+	g.watchFonts(ctx)
+	go g.watchFiles()
+}
+
+// watchFiles runs the callbacks of changed files on the UI thread until the UI is closed
+func (g *gui) watchFiles() {
+	ticker := time.NewTicker(reloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-g.context.Done():
+			return
+		case <-ticker.C:
+			for _, fn := range g.watcher.poll() {
+				g.Post(fn)
+			}
+		}
+	}
+}
+
+// watchStylesheet reloads the stylesheet when its file changes
+func (g *gui) watchStylesheet(sheet ReloadableStylesheet) {
+	path := sheet.Path()
+	if path == "" {
+		return
+	}
+	g.watcher.watch(path, func() {
+		if err := sheet.Reload(); err != nil {
+			logError("could not reload stylesheet", path+":\n"+err.Error())
+			return
+		}
+		logInfo("reloaded " + path)
+	})
+}
+
+// watchImage frees the image when its file changes, so that it is loaded again by the next render
+func (g *gui) watchImage(path string) {
+	if isURL(path) {
+		return
+	}
+	g.watcher.watch(path, func() {
+		g.resources.evict(g.ctx, path)
+		logInfo("reloaded " + path)
+	})
+}
+
+// reloadedFonts is the canvas of a UI that reloads its fonts. nanovgo keeps using the first font created
+// with a name and can't free fonts, so a changed font is created under a new name that is used instead.
+// Every version of a font stays in memory, but a version is only created once, also when a file
+// changes back to an earlier version.
+type reloadedFonts struct {
+	canvas
+	faces    map[string]string            // the name of the latest version of a font
+	versions map[[sha256.Size]byte]string // the name a font was created with, by its contents
+}
+
+func newReloadedFonts(ctx canvas) *reloadedFonts {
+	return &reloadedFonts{
+		canvas:   ctx,
+		faces:    map[string]string{},
+		versions: map[[sha256.Size]byte]string{},
+	}
+}
+
+func (c *reloadedFonts) SetFontFace(font string) {
+	if face, ok := c.faces[font]; ok {
+		font = face
+	}
+	c.canvas.SetFontFace(font)
+}
+
+// load switches the font to the given contents, creating the font if they are new
+func (c *reloadedFonts) load(font string, data []byte) bool {
+	hash := sha256.Sum256(data)
+	face, ok := c.versions[hash]
+	if !ok {
+		face = font + "#" + strconv.Itoa(len(c.versions))
+		if c.canvas.CreateFontFromMemory(face, data, 0) == -1 {
+			return false
+		}
+		c.versions[hash] = face
+	}
+	c.faces[font] = face
+	return true
+}
+
+// watchFonts loads the fonts again when their files change
+func (g *gui) watchFonts(ctx *reloadedFonts) {
+	for _, font := range fonts {
+		font, path := font, fontPath(font)
+		// the fonts that are already loaded are used again when a file changes back
+		if data, err := ioutil.ReadFile(path); err == nil {
+			ctx.versions[sha256.Sum256(data)] = font
+		}
+
+		g.watcher.watch(path, func() {
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				logError("could not reload font", path+":", err)
+				return
+			}
+			if !ctx.load(font, data) {
+				logError("could not reload font", path)
+				return
+			}
+			logInfo("reloaded " + path)
+		})
+	}
+}
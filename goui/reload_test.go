@@ -0,0 +1,218 @@
+package goui
+
+import (
+	"image"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "goui")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// touch changes the modification time of the file, a second later every time so that
+// file systems that only store seconds see the change
+func touch(t *testing.T, path string) {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	later := info.ModTime().Add(time.Second)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// runChanged runs the callbacks of the changed files and returns how many there were
+func runChanged(w *fileWatcher) int {
+	changed := w.poll()
+	for _, fn := range changed {
+		fn()
+	}
+	return len(changed)
+}
+
+func TestFileWatcher(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "main.css")
+	writeFile(t, path, []byte(".a {}"))
+
+	w := newFileWatcher()
+	calls := 0
+	w.watch(path, func() { calls++ })
+	w.watch(path, func() { t.Error("the second callback for a file was called") })
+
+	if n := runChanged(w); n != 0 {
+		t.Errorf("%v changes before the file changed", n)
+	}
+	touch(t, path)
+	if n := runChanged(w); n != 1 || calls != 1 {
+		t.Errorf("%v changes and %v calls after the file changed", n, calls)
+	}
+	if n := runChanged(w); n != 0 {
+		t.Errorf("the same change was reported %v times again", n)
+	}
+
+	os.Remove(path)
+	if n := runChanged(w); n != 0 {
+		t.Errorf("%v changes after the file was removed", n)
+	}
+	writeFile(t, path, []byte(".a {}"))
+	touch(t, path)
+	if n := runChanged(w); n != 1 || calls != 2 {
+		t.Errorf("%v changes and %v calls after the file was created again", n, calls)
+	}
+}
+
+type fileSheet struct {
+	path    string
+	reloads int
+}
+
+func (s *fileSheet) Class(name string) (Styles, bool) { return NewStyles(), false }
+func (s *fileSheet) Path() string                     { return s.path }
+func (s *fileSheet) Reload() error                    { s.reloads++; return nil }
+
+func TestReloadStylesheet(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	sheet := &fileSheet{path: filepath.Join(dir, "main.css")}
+	writeFile(t, sheet.path, []byte(".a {}"))
+
+	d := newTestDriver(t, 100, 100, func(g UI) {
+		g.Stylesheet(sheet)
+		g.Text("hello")
+	})
+	d.g.watcher = newFileWatcher()
+	d.g.Rerender()
+	d.Step()
+
+	touch(t, sheet.path)
+	runChanged(d.g.watcher)
+	if sheet.reloads != 1 {
+		t.Errorf("the stylesheet was reloaded %v times", sheet.reloads)
+	}
+}
+
+func writeImage(t *testing.T, path string, width, height int) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := png.Encode(file, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadImage(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "image.png")
+	writeImage(t, path, 2, 2)
+
+	d := newTestDriver(t, 100, 100, func(g UI) {})
+	d.g.watcher = newFileWatcher()
+	d.g.resources.onCreate = d.g.watchImage
+	d.g.renderFunc = func(g UI) { g.Image(path) }
+	d.g.Rerender()
+	d.Step()
+
+	writeImage(t, path, 3, 3)
+	touch(t, path)
+	runChanged(d.g.watcher)
+	d.g.Rerender()
+	d.Step()
+	if res := d.g.resources.resources[path]; res == nil || res.width != 3 {
+		t.Errorf("the changed image was not loaded again: %+v", res)
+	}
+}
+
+func TestEvictWhileLoading(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "image.png")
+	writeImage(t, path, 2, 2)
+
+	c := newResourceCache(nil)
+	c.resources[path] = &resource{state: resourceLoading}
+	c.evict(newSoftCanvas(1, 1), path)
+	res, ok := c.resources[path]
+	if !ok || !res.stale {
+		t.Fatalf("a loading image was freed instead of being marked as changed: %+v", res)
+	}
+
+	// the load that was running when the file changed loads the file again
+	c.load(path)
+	if res.stale || res.state != resourceLoaded || res.width != 2 {
+		t.Errorf("the changed image was not loaded: %+v", res)
+	}
+}
+
+func TestReloadFonts(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	for _, font := range fonts {
+		data, err := ioutil.ReadFile(fontPath(font))
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, filepath.Join(dir, font+".ttf"), data)
+	}
+	defer FontDirectory(fontDirectory)
+	FontDirectory(dir)
+
+	soft := newSoftCanvas(100, 100)
+	if err := loadFonts(soft); err != nil {
+		t.Fatal(err)
+	}
+	g := newGUI(soft, func(g UI) {})
+	g.watcher = newFileWatcher()
+	ctx := newReloadedFonts(g.ctx)
+	g.watchFonts(ctx)
+
+	regular, err := ioutil.ReadFile(fontPath(FontRegular))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a version of the font that isn't loaded yet, fonts of the same contents are reused
+	edited := append(append([]byte{}, regular...), 0, 0, 0, 0)
+	change := func(data []byte) string {
+		writeFile(t, fontPath(FontRegular), data)
+		touch(t, fontPath(FontRegular))
+		runChanged(g.watcher)
+		ctx.SetFontFace(FontRegular)
+		return soft.state().font
+	}
+
+	loaded := len(soft.fonts)
+	changed := change(edited)
+	if changed == FontRegular || len(soft.fonts) != loaded+1 {
+		t.Errorf("the changed font is not used: %v, %v fonts", changed, len(soft.fonts))
+	}
+	if face := change(regular); face != FontRegular {
+		t.Errorf("the font that was loaded first is not used again: %v", face)
+	}
+	if face := change(edited); face != changed || len(soft.fonts) != loaded+1 {
+		t.Errorf("the font was created again: %v, %v fonts", face, len(soft.fonts))
+	}
+}
@@ -0,0 +1,224 @@
+package goui
+
+import (
+	"image/color"
+
+	"github.com/kjk/flex"
+)
+
+const (
+	scrollSpeed        = 40 // pixels per step of the mouse wheel
+	scrollbarSize      = 8
+	minScrollbarLength = 20
+)
+
+// scrollState is the scroll offset of a box with OverflowScroll, kept between renders
+type scrollState struct {
+	x, y float32
+
+	contentWidth, contentHeight float32
+	viewWidth, viewHeight       float32
+}
+
+func (s *scrollState) clamp() {
+	s.x = maxF(0, minF(s.x, s.contentWidth-s.viewWidth))
+	s.y = maxF(0, minF(s.y, s.contentHeight-s.viewHeight))
+}
+
+// scrollbar is the position of a scrollbar along its axis, relative to the box
+type scrollbar struct {
+	trackLength float32
+	thumbPos    float32
+	thumbLength float32
+}
+
+func (s *scrollState) scrollbar(vertical bool) (scrollbar, bool) {
+	view, content, offset := s.viewWidth, s.contentWidth, s.x
+	if vertical {
+		view, content, offset = s.viewHeight, s.contentHeight, s.y
+	}
+	if content <= view {
+		return scrollbar{}, false
+	}
+
+	bar := scrollbar{
+		trackLength: view,
+		thumbLength: maxF(minScrollbarLength, view*view/content),
+	}
+	bar.thumbPos = (bar.trackLength - bar.thumbLength) * offset / (content - view)
+	return bar, true
+}
+
+// thumbRect returns the absolute rectangle of the scrollbar thumb of a box at (x, y)
+func (s *scrollState) thumbRect(x, y float32, vertical bool) (float32, float32, float32, float32, bool) {
+	bar, ok := s.scrollbar(vertical)
+	if !ok {
+		return 0, 0, 0, 0, false
+	}
+	if vertical {
+		return x + s.viewWidth - scrollbarSize, y + bar.thumbPos, scrollbarSize, bar.thumbLength, true
+	}
+	return x + bar.thumbPos, y + s.viewHeight - scrollbarSize, bar.thumbLength, scrollbarSize, true
+}
+
+func drawScrollbars(ctx canvas, x, y float32, s *scrollState, col color.Color) {
+	for _, vertical := range []bool{true, false} {
+		if thumbX, thumbY, thumbW, thumbH, ok := s.thumbRect(x, y, vertical); ok {
+			ctx.BeginPath()
+			ctx.SetFillColor(colorToNanoColor(col))
+			ctx.RoundedRect(thumbX, thumbY, thumbW, thumbH, scrollbarSize/2)
+			ctx.Fill()
+		}
+	}
+}
+
+func clipsChildren(s Styles) bool {
+	return s.overflow == OverflowHidden || s.overflow == OverflowScroll
+}
+
+// updateScroll attaches the scroll state to every scrolling box and updates the size of its content
+func (g *gui) updateScroll(w *widgetContainer) {
+	box, ok := w.widget.(*boxWidget)
+	if !ok {
+		return
+	}
+
+	box.scroll = nil
+	if w.handle.styles.overflow == OverflowScroll {
+		state := g.widgetState(w, "scroll", func() interface{} { return &scrollState{} }).(*scrollState)
+		state.viewWidth = w.layout.LayoutGetWidth()
+		state.viewHeight = w.layout.LayoutGetHeight()
+		state.contentWidth, state.contentHeight = contentSize(w)
+		state.clamp()
+		box.scroll = state
+	}
+
+	for _, child := range box.children {
+		g.updateScroll(child)
+	}
+}
+
+// contentSize returns the size needed to show all the children of a box
+func contentSize(w *widgetContainer) (float32, float32) {
+	width, height := float32(0), float32(0)
+	for _, child := range w.widget.(*boxWidget).children {
+		l := child.layout
+		width = maxF(width, l.LayoutGetLeft()+l.LayoutGetWidth()+l.LayoutGetMargin(flex.EdgeRight))
+		height = maxF(height, l.LayoutGetTop()+l.LayoutGetHeight()+l.LayoutGetMargin(flex.EdgeBottom))
+	}
+	return width + inset(w.layout, flex.EdgeRight), height + inset(w.layout, flex.EdgeBottom)
+}
+
+// dispatchScroll scrolls the deepest box under the cursor that can scroll in the direction of the event
+func (g *gui) dispatchScroll(ev ScrollEvent) bool {
+	handled := false
+
+	if g.root != nil {
+		for w := hitTest(g.root, g.mouseX, g.mouseY); w != nil; w = w.parent {
+			box, ok := w.widget.(*boxWidget)
+			if !ok || box.scroll == nil {
+				continue
+			}
+			oldX, oldY := box.scroll.x, box.scroll.y
+			box.scroll.x -= float32(ev.X) * scrollSpeed
+			box.scroll.y -= float32(ev.Y) * scrollSpeed
+			box.scroll.clamp()
+			if box.scroll.x != oldX || box.scroll.y != oldY {
+				handled = true
+				break
+			}
+		}
+	}
+
+	if g.scrollCb != nil {
+		g.scrollCb(ev)
+		handled = true
+	}
+	return handled
+}
+
+// scrollDrag is an active drag of a scrollbar thumb
+type scrollDrag struct {
+	state       *scrollState
+	vertical    bool
+	inverse     transform // from the window to the box, the mouse is followed in the coordinates of the box
+	startMouse  float32
+	startOffset float32
+}
+
+// startScrollDrag starts dragging a scrollbar if the press was on a scrollbar thumb
+func (g *gui) startScrollDrag(ev ClickEvent) bool {
+	if g.root == nil || ev.Button != MouseButtonLeft {
+		return false
+	}
+	x, y := float32(ev.X), float32(ev.Y)
+
+	for w := hitTest(g.root, x, y); w != nil; w = w.parent {
+		box, ok := w.widget.(*boxWidget)
+		if !ok || box.scroll == nil {
+			continue
+		}
+		for _, vertical := range []bool{true, false} {
+			thumbX, thumbY, thumbW, thumbH, ok := box.scroll.thumbRect(w.x, w.y, vertical)
+			localX, localY := w.inverse.apply(x, y)
+			if !ok || localX < thumbX || localX >= thumbX+thumbW || localY < thumbY || localY >= thumbY+thumbH {
+				continue
+			}
+			g.drag = &scrollDrag{state: box.scroll, vertical: vertical, inverse: w.inverse, startMouse: localX, startOffset: box.scroll.x}
+			if vertical {
+				g.drag.startMouse, g.drag.startOffset = localY, box.scroll.y
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// updateScrollDrag moves the scroll offset along with the mouse, so the thumb follows the cursor
+func (g *gui) updateScrollDrag(x, y float32) {
+	d := g.drag
+	bar, ok := d.state.scrollbar(d.vertical)
+	if !ok || bar.trackLength <= bar.thumbLength {
+		return
+	}
+	x, y = d.inverse.apply(x, y)
+
+	if d.vertical {
+		ratio := (d.state.contentHeight - d.state.viewHeight) / (bar.trackLength - bar.thumbLength)
+		d.state.y = d.startOffset + (y-d.startMouse)*ratio
+	} else {
+		ratio := (d.state.contentWidth - d.state.viewWidth) / (bar.trackLength - bar.thumbLength)
+		d.state.x = d.startOffset + (x-d.startMouse)*ratio
+	}
+	d.state.clamp()
+}
+
+// scrollIntoView scrolls all the scrolling ancestors of a widget so that it becomes visible
+func scrollIntoView(w *widgetContainer) {
+	x, y := w.x, w.y
+	for p := w.parent; p != nil; p = p.parent {
+		box := p.widget.(*boxWidget)
+		if box.scroll == nil {
+			continue
+		}
+		s := box.scroll
+		oldX, oldY := s.x, s.y
+
+		// position of the widget inside the content of the box
+		relX, relY := x-p.x+s.x, y-p.y+s.y
+		if relX < s.x {
+			s.x = relX
+		} else if relX+w.width > s.x+s.viewWidth {
+			s.x = relX + w.width - s.viewWidth
+		}
+		if relY < s.y {
+			s.y = relY
+		} else if relY+w.height > s.y+s.viewHeight {
+			s.y = relY + w.height - s.viewHeight
+		}
+		s.clamp()
+
+		x -= s.x - oldX
+		y -= s.y - oldY
+	}
+}
@@ -0,0 +1,87 @@
+package goui
+
+import (
+	"fmt"
+	"testing"
+)
+
+// scrollingList renders a list of 5 items of 50px in a box of 100px, with the given styles on the box
+func scrollingList(styles Styles) func(g UI) {
+	return func(g UI) {
+		g.Box(func() {
+			for i := 0; i < 5; i++ {
+				g.Box(func() {}).Key(fmt.Sprint("item", i)).Styles(NewStyles().Height(50))
+			}
+		}).Key("list").Styles(NewStyles().Width(100).Height(100).Overflow(OverflowScroll), styles)
+	}
+}
+
+func TestScrollWheel(t *testing.T) {
+	d := newTestDriver(t, 200, 200, scrollingList(NewStyles()))
+
+	d.MoveMouse(50, 50)
+	d.Scroll(ScrollEvent{Y: -1})
+	d.Step()
+	if item := find(t, d, "item1"); item.Y != 50-scrollSpeed {
+		t.Errorf("item1 is at %v after scrolling", item.Y)
+	}
+
+	d.Scroll(ScrollEvent{Y: -100})
+	d.Step()
+	if item := find(t, d, "item4"); item.Y != 50 {
+		t.Errorf("item4 is at %v after scrolling past the end", item.Y)
+	}
+
+	d.Scroll(ScrollEvent{Y: 100})
+	d.Step()
+	if item := find(t, d, "item0"); item.Y != 0 {
+		t.Errorf("item0 is at %v after scrolling past the start", item.Y)
+	}
+	if list := find(t, d, "list"); list.Y != 0 || list.Height != 100 {
+		t.Errorf("list moved to %v with height %v", list.Y, list.Height)
+	}
+}
+
+func TestScrollWheelOutside(t *testing.T) {
+	d := newTestDriver(t, 200, 200, scrollingList(NewStyles()))
+
+	d.MoveMouse(150, 150)
+	d.Scroll(ScrollEvent{Y: -1})
+	d.Step()
+	if item := find(t, d, "item0"); item.Y != 0 {
+		t.Errorf("scrolling outside of the list moved item0 to %v", item.Y)
+	}
+}
+
+// the thumb is 40px long, since the view is 100px of 250px, and the content scrolls 2.5px per pixel it is dragged
+func TestScrollThumbDrag(t *testing.T) {
+	d := newTestDriver(t, 200, 200, scrollingList(NewStyles()))
+
+	d.Press(ClickEvent{X: 96, Y: 20})
+	d.MoveMouse(96, 50)
+	d.Step()
+	if item := find(t, d, "item1"); item.Y != 50-75 {
+		t.Errorf("item1 is at %v after dragging the thumb 30px", item.Y)
+	}
+
+	d.MoveMouse(96, 500)
+	d.Release(ClickEvent{X: 96, Y: 500})
+	d.Step()
+	if item := find(t, d, "item4"); item.Y != 50 {
+		t.Errorf("item4 is at %v after dragging the thumb past the end", item.Y)
+	}
+}
+
+func TestScrollThumbDragScaled(t *testing.T) {
+	// the list is at 60, 60 and scaled around its center at 110, 110, so the thumb at 96, 20 in the list is
+	// at 202, 50 in the window and moving the mouse 60px moves it 30px
+	d := newTestDriver(t, 300, 300, scrollingList(NewStyles().Margin(EdgeAll, 60).Scale(2, 2)))
+
+	d.Press(ClickEvent{X: 202, Y: 50})
+	d.MoveMouse(202, 110)
+	d.Release(ClickEvent{X: 202, Y: 110})
+	d.Step()
+	if item := find(t, d, "item1"); item.Y != 60+50-75 {
+		t.Errorf("item1 is at %v after dragging the thumb 30px in the list", item.Y)
+	}
+}
@@ -0,0 +1,73 @@
+package goui
+
+import (
+	"image/color"
+
+	"github.com/shibukawa/nanovgo"
+)
+
+type boxShadow struct {
+	offsetX, offsetY float64
+	blur, spread     float64
+	color            color.Color
+	inset            bool
+}
+
+// appendShadow adds a shadow to a copy of the list, so that styles copied from each other don't share it
+func appendShadow(shadows []boxShadow, shadow boxShadow) []boxShadow {
+	return append(append([]boxShadow{}, shadows...), shadow)
+}
+
+// drawShadows draws the shadows that go under the box, the part under the box itself is left out
+// so that the shadows don't show through transparent backgrounds
+func drawShadows(ctx canvas, x, y, w, h float32, r [4]float32, shadows []boxShadow) {
+	// the first shadow is on top
+	for i := len(shadows) - 1; i >= 0; i-- {
+		shadow := shadows[i]
+		if shadow.inset || shadow.color == nil {
+			continue
+		}
+		blur, spread := float32(shadow.blur), float32(shadow.spread)
+		shadowX := x + float32(shadow.offsetX) - spread
+		shadowY := y + float32(shadow.offsetY) - spread
+		shadowW, shadowH := w+2*spread, h+2*spread
+		col := colorToNanoColor(shadow.color)
+
+		ctx.BeginPath()
+		ctx.Rect(shadowX-blur, shadowY-blur, shadowW+2*blur, shadowH+2*blur)
+		roundedRect(ctx, x, y, w, h, r)
+		ctx.PathWinding(nanovgo.Hole)
+		ctx.SetFillPaint(boxGradient(shadowX, shadowY, shadowW, shadowH, maxRadius(r)+spread, blur, col, transparent(col)))
+		ctx.Fill()
+	}
+}
+
+// drawInsetShadows draws the inset shadows inside the box
+func drawInsetShadows(ctx canvas, x, y, w, h float32, r [4]float32, shadows []boxShadow) {
+	for i := len(shadows) - 1; i >= 0; i-- {
+		shadow := shadows[i]
+		if !shadow.inset || shadow.color == nil {
+			continue
+		}
+		blur, spread := float32(shadow.blur), float32(shadow.spread)
+		shadowX := x + float32(shadow.offsetX) + spread
+		shadowY := y + float32(shadow.offsetY) + spread
+		shadowW, shadowH := maxF(0, w-2*spread), maxF(0, h-2*spread)
+		col := colorToNanoColor(shadow.color)
+
+		ctx.BeginPath()
+		roundedRect(ctx, x, y, w, h, r)
+		ctx.SetFillPaint(boxGradient(shadowX, shadowY, shadowW, shadowH, maxF(0, maxRadius(r)-spread), blur, transparent(col), col))
+		ctx.Fill()
+	}
+}
+
+// maxRadius is used for box gradients, which have the same radius in every corner
+func maxRadius(r [4]float32) float32 {
+	return maxF(maxF(r[0], r[1]), maxF(r[2], r[3]))
+}
+
+func transparent(c nanovgo.Color) nanovgo.Color {
+	c.A = 0
+	return c
+}
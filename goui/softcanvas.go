@@ -0,0 +1,912 @@
+package goui
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/shibukawa/nanovgo"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// softCanvas renders into an image in memory without OpenGL. It supports everything the widgets draw:
+// paths of lines, rects and rounded rects, colors, gradients, image patterns, scissors, text,
+// transformations and global alpha. Strokes have miter joins and butt caps, the defaults of nanovgo.
+type softCanvas struct {
+	img    *image.RGBA
+	mask   *image.Alpha
+	raster *vector.Rasterizer
+
+	states []softState
+	path   []softPath
+
+	fonts     map[string]*softFont
+	images    map[int]image.Image
+	nextImage int
+}
+
+type softPoint struct {
+	x, y float32
+}
+
+type softPath struct {
+	points []softPoint
+	closed bool
+	hole   bool
+}
+
+// softState is the part of the canvas that is saved and restored, like the nanovgo state
+type softState struct {
+	fillColor   nanovgo.Color
+	fillPaint   *paint
+	strokeColor nanovgo.Color
+	strokeWidth float32
+	scissor     image.Rectangle
+	clip        *image.Alpha // coverage of scissors that are not axis aligned, nil if there are none
+	xform       transform
+	alpha       float32
+
+	font     string
+	fontSize float32
+	align    nanovgo.Align
+}
+
+type softFont struct {
+	font *opentype.Font
+	// nanovgo sizes fonts by the height from the ascender to the descender instead of the em size
+	heightPerEm float64
+	faces       map[float32]font.Face
+}
+
+func newSoftCanvas(width, height int) *softCanvas {
+	c := &softCanvas{
+		fonts:  map[string]*softFont{},
+		images: map[int]image.Image{},
+	}
+	c.resize(width, height)
+	return c
+}
+
+// resize replaces the image with a new transparent one and resets the state
+func (c *softCanvas) resize(width, height int) {
+	c.img = image.NewRGBA(image.Rect(0, 0, width, height))
+	c.mask = image.NewAlpha(image.Rect(0, 0, width, height))
+	c.raster = vector.NewRasterizer(width, height)
+
+	// same defaults as nanovgo
+	c.states = []softState{{
+		fillColor:   nanovgo.RGBA(255, 255, 255, 255),
+		strokeColor: nanovgo.RGBA(0, 0, 0, 255),
+		strokeWidth: 1,
+		scissor:     c.img.Bounds(),
+		xform:       identityTransform,
+		alpha:       1,
+		fontSize:    16,
+		align:       nanovgo.AlignLeft | nanovgo.AlignBaseline,
+	}}
+}
+
+func (c *softCanvas) state() *softState {
+	return &c.states[len(c.states)-1]
+}
+
+// clear makes the image transparent again before a new frame
+func (c *softCanvas) clear() {
+	for i := range c.img.Pix {
+		c.img.Pix[i] = 0
+	}
+}
+
+func (c *softCanvas) Save() {
+	c.states = append(c.states, *c.state())
+}
+
+func (c *softCanvas) Restore() {
+	if len(c.states) > 1 {
+		c.states = c.states[:len(c.states)-1]
+	}
+}
+
+func (c *softCanvas) SetGlobalAlpha(alpha float32) {
+	c.state().alpha = alpha
+}
+
+func (c *softCanvas) Translate(x, y float32) {
+	s := c.state()
+	s.xform = s.xform.multiply(translation(x, y))
+}
+
+func (c *softCanvas) Rotate(angle float32) {
+	s := c.state()
+	s.xform = s.xform.multiply(rotation(angle))
+}
+
+func (c *softCanvas) Scale(x, y float32) {
+	s := c.state()
+	s.xform = s.xform.multiply(scaling(x, y))
+}
+
+// point transforms a point given to the path functions into the image
+func (c *softCanvas) point(x, y float32) softPoint {
+	x, y = c.state().xform.apply(x, y)
+	return softPoint{x, y}
+}
+
+func (c *softCanvas) points(points []softPoint) []softPoint {
+	for i, p := range points {
+		points[i] = c.point(p.x, p.y)
+	}
+	return points
+}
+
+// IntersectScissor limits drawing to the rectangle. The scissor keeps the bounding box of the transformed
+// rectangle, and a rotated or skewed rectangle is also rasterized into the clip mask.
+func (c *softCanvas) IntersectScissor(x, y, w, h float32) {
+	s := c.state()
+	corners := c.points([]softPoint{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}})
+	minX, minY, maxX, maxY := corners[0].x, corners[0].y, corners[0].x, corners[0].y
+	for _, p := range corners[1:] {
+		minX, minY = minF(minX, p.x), minF(minY, p.y)
+		maxX, maxY = maxF(maxX, p.x), maxF(maxY, p.y)
+	}
+	r := image.Rect(roundInt(minX), roundInt(minY), roundInt(maxX), roundInt(maxY))
+	s.scissor = s.scissor.Intersect(r)
+	if s.xform[1] == 0 && s.xform[2] == 0 {
+		return
+	}
+
+	// the clip is shared with saved states, so it is replaced instead of changed
+	clip := image.NewAlpha(c.img.Bounds())
+	c.raster.Reset(c.img.Bounds().Dx(), c.img.Bounds().Dy())
+	c.raster.MoveTo(corners[0].x, corners[0].y)
+	for _, p := range corners[1:] {
+		c.raster.LineTo(p.x, p.y)
+	}
+	c.raster.ClosePath()
+	c.raster.Draw(clip, clip.Bounds(), image.Opaque, image.Point{})
+	if s.clip != nil {
+		for i, coverage := range s.clip.Pix {
+			clip.Pix[i] = uint8((int(clip.Pix[i])*int(coverage) + 127) / 255)
+		}
+	}
+	s.clip = clip
+}
+
+func (c *softCanvas) BeginPath() {
+	c.path = nil
+}
+
+func (c *softCanvas) MoveTo(x, y float32) {
+	c.path = append(c.path, softPath{points: []softPoint{c.point(x, y)}})
+}
+
+func (c *softCanvas) LineTo(x, y float32) {
+	if len(c.path) == 0 {
+		c.MoveTo(x, y)
+		return
+	}
+	last := &c.path[len(c.path)-1]
+	last.points = append(last.points, c.point(x, y))
+}
+
+// BezierTo adds a cubic bezier curve, split into lines
+func (c *softCanvas) BezierTo(c1x, c1y, c2x, c2y, x, y float32) {
+	if len(c.path) == 0 {
+		c.MoveTo(x, y)
+		return
+	}
+	last := &c.path[len(c.path)-1]
+	// bezier curves stay the same when their points are transformed
+	p0 := last.points[len(last.points)-1]
+	p1, p2, p3 := c.point(c1x, c1y), c.point(c2x, c2y), c.point(x, y)
+
+	const segments = 12
+	for i := 1; i <= segments; i++ {
+		t := float32(i) / segments
+		u := 1 - t
+		last.points = append(last.points, softPoint{
+			x: u*u*u*p0.x + 3*u*u*t*p1.x + 3*u*t*t*p2.x + t*t*t*p3.x,
+			y: u*u*u*p0.y + 3*u*u*t*p1.y + 3*u*t*t*p2.y + t*t*t*p3.y,
+		})
+	}
+}
+
+// Arc adds an arc to the current path, or starts a new path with it
+func (c *softCanvas) Arc(cx, cy, r, a0, a1 float32, dir nanovgo.Direction) {
+	if dir == nanovgo.CounterClockwise {
+		for a1 > a0 {
+			a1 -= 2 * math.Pi
+		}
+	} else {
+		for a1 < a0 {
+			a1 += 2 * math.Pi
+		}
+	}
+	points := c.points(appendArc(nil, cx, cy, r, float64(a0), float64(a1)))
+	if len(c.path) == 0 {
+		c.path = append(c.path, softPath{})
+	}
+	last := &c.path[len(c.path)-1]
+	last.points = append(last.points, points...)
+}
+
+func (c *softCanvas) ClosePath() {
+	if len(c.path) > 0 {
+		c.path[len(c.path)-1].closed = true
+	}
+}
+
+func (c *softCanvas) PathWinding(winding nanovgo.Winding) {
+	if len(c.path) > 0 {
+		c.path[len(c.path)-1].hole = winding == nanovgo.Hole
+	}
+}
+
+func (c *softCanvas) Circle(cx, cy, r float32) {
+	points := c.points(appendArc(nil, cx, cy, r, 0, 2*math.Pi))
+	c.path = append(c.path, softPath{points: points[:len(points)-1], closed: true})
+}
+
+func (c *softCanvas) Rect(x, y, w, h float32) {
+	c.path = append(c.path, softPath{
+		points: c.points([]softPoint{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}}),
+		closed: true,
+	})
+}
+
+func (c *softCanvas) RoundedRect(x, y, w, h, r float32) {
+	r = minF(r, minF(w, h)/2)
+	if r < 0.1 {
+		c.Rect(x, y, w, h)
+		return
+	}
+
+	var points []softPoint
+	points = appendArc(points, x+r, y+r, r, math.Pi, 1.5*math.Pi)
+	points = appendArc(points, x+w-r, y+r, r, 1.5*math.Pi, 2*math.Pi)
+	points = appendArc(points, x+w-r, y+h-r, r, 0, 0.5*math.Pi)
+	points = appendArc(points, x+r, y+h-r, r, 0.5*math.Pi, math.Pi)
+	c.path = append(c.path, softPath{points: c.points(points), closed: true})
+}
+
+// appendArc adds the points of an arc, short enough lines are used for it to look round.
+// Every eighth of a circle has a line, so that small circles like dots still have an area.
+func appendArc(points []softPoint, cx, cy, r float32, start, end float64) []softPoint {
+	segments := int(math.Ceil(float64(r) * math.Abs(end-start) / math.Pi))
+	if minSegments := int(math.Ceil(math.Abs(end-start) / (math.Pi / 4))); segments < minSegments {
+		segments = minSegments
+	}
+	if segments < 2 {
+		segments = 2
+	} else if segments > 64 {
+		segments = 64
+	}
+	for i := 0; i <= segments; i++ {
+		angle := start + (end-start)*float64(i)/float64(segments)
+		points = append(points, softPoint{
+			x: cx + r*float32(math.Cos(angle)),
+			y: cy + r*float32(math.Sin(angle)),
+		})
+	}
+	return points
+}
+
+func (c *softCanvas) SetFillColor(color nanovgo.Color) {
+	s := c.state()
+	s.fillColor = color
+	s.fillPaint = nil
+}
+
+func (c *softCanvas) SetFillPaint(p paint) {
+	c.state().fillPaint = &p
+}
+
+func (c *softCanvas) SetStrokeColor(color nanovgo.Color) {
+	c.state().strokeColor = color
+}
+
+func (c *softCanvas) SetStrokeWidth(width float32) {
+	c.state().strokeWidth = width
+}
+
+func (c *softCanvas) Fill() {
+	s := c.state()
+	var src image.Image = image.NewUniform(softColor(s.fillColor))
+	if s.fillPaint != nil {
+		src = c.paintSource(*s.fillPaint)
+	}
+	c.fillPath(src, true)
+}
+
+// fillPath draws src through the path. With orient the paths are turned so that holes go the other way
+// around than solid paths and their coverage cancels out, otherwise the paths keep their own direction.
+func (c *softCanvas) fillPath(src image.Image, orient bool) {
+	c.raster.Reset(c.img.Bounds().Dx(), c.img.Bounds().Dy())
+	for _, path := range c.path {
+		points := path.points
+		if len(points) < 3 {
+			continue
+		}
+		if orient && (polygonArea(points) < 0) != path.hole {
+			points = reversePoints(points)
+		}
+		c.raster.MoveTo(points[0].x, points[0].y)
+		for _, p := range points[1:] {
+			c.raster.LineTo(p.x, p.y)
+		}
+		c.raster.ClosePath()
+	}
+	c.drawPath(src)
+}
+
+func polygonArea(points []softPoint) float32 {
+	area := float32(0)
+	for i, p := range points {
+		next := points[(i+1)%len(points)]
+		area += p.x*next.y - next.x*p.y
+	}
+	return area / 2
+}
+
+func reversePoints(points []softPoint) []softPoint {
+	reversed := make([]softPoint, len(points))
+	for i, p := range points {
+		reversed[len(points)-1-i] = p
+	}
+	return reversed
+}
+
+// miterLimit is the longest miter, in half stroke widths, before a join is beveled like in nanovgo
+const miterLimit = 10
+
+// Stroke draws every line of the path as a rectangle, and fills the gap on the outside of the corners where
+// lines meet with a miter, or a bevel if the miter would be too long. The ends of open paths are butt caps.
+func (c *softCanvas) Stroke() {
+	s := c.state()
+	halfWidth := s.strokeWidth * s.xform.averageScale() / 2
+	c.raster.Reset(c.img.Bounds().Dx(), c.img.Bounds().Dy())
+	for _, path := range c.path {
+		var points []softPoint
+		for _, p := range path.points {
+			if len(points) == 0 || p != points[len(points)-1] {
+				points = append(points, p)
+			}
+		}
+		if path.closed && len(points) > 1 && points[0] == points[len(points)-1] {
+			points = points[:len(points)-1]
+		}
+		if len(points) < 2 {
+			continue
+		}
+
+		segments := len(points) - 1
+		if path.closed {
+			segments = len(points)
+		}
+		for i := 0; i < segments; i++ {
+			c.addLine(points[i], points[(i+1)%len(points)], halfWidth)
+		}
+		for i := range points {
+			if !path.closed && (i == 0 || i == len(points)-1) {
+				continue
+			}
+			prev := points[(i+len(points)-1)%len(points)]
+			c.addJoin(prev, points[i], points[(i+1)%len(points)], halfWidth)
+		}
+	}
+	c.drawPath(image.NewUniform(softColor(s.strokeColor)))
+}
+
+func (c *softCanvas) addLine(a, b softPoint, halfWidth float32) {
+	nx, ny := normal(a, b)
+	nx, ny = nx*halfWidth, ny*halfWidth
+	c.addPolygon(
+		softPoint{a.x + nx, a.y + ny}, softPoint{b.x + nx, b.y + ny},
+		softPoint{b.x - nx, b.y - ny}, softPoint{a.x - nx, a.y - ny},
+	)
+}
+
+// addJoin fills the corner at b between the lines from a to b and from b to c
+func (c *softCanvas) addJoin(a, b, cp softPoint, halfWidth float32) {
+	n0x, n0y := normal(a, b)
+	n1x, n1y := normal(b, cp)
+	cross := (b.x-a.x)*(cp.y-b.y) - (b.y-a.y)*(cp.x-b.x)
+	if cross == 0 && n0x*n1x+n0y*n1y > 0 {
+		return
+	}
+	// the gap is on the side the path turns away from
+	side := halfWidth
+	if cross > 0 {
+		side = -halfWidth
+	}
+	start := softPoint{b.x + n0x*side, b.y + n0y*side}
+	end := softPoint{b.x + n1x*side, b.y + n1y*side}
+
+	dot := n0x*n1x + n0y*n1y
+	if 1+dot > 2.0/(miterLimit*miterLimit) {
+		// the miter is the sum of the normals, scaled to reach the outer edges of both lines
+		scale := side / (1 + dot)
+		miter := softPoint{b.x + (n0x+n1x)*scale, b.y + (n0y+n1y)*scale}
+		c.addPolygon(b, start, miter, end)
+	} else {
+		c.addPolygon(b, start, end)
+	}
+}
+
+// addPolygon adds a polygon to the rasterizer, turned so that overlapping polygons add up instead of cancelling out
+func (c *softCanvas) addPolygon(points ...softPoint) {
+	if polygonArea(points) < 0 {
+		points = reversePoints(points)
+	}
+	c.raster.MoveTo(points[0].x, points[0].y)
+	for _, p := range points[1:] {
+		c.raster.LineTo(p.x, p.y)
+	}
+	c.raster.ClosePath()
+}
+
+// normal returns the unit vector perpendicular to the line from a to b
+func normal(a, b softPoint) (float32, float32) {
+	dx, dy := b.x-a.x, b.y-a.y
+	length := float32(math.Hypot(float64(dx), float64(dy)))
+	return -dy / length, dx / length
+}
+
+// drawPath draws src onto the image through the coverage of the rasterized path, inside the scissor
+func (c *softCanvas) drawPath(src image.Image) {
+	for i := range c.mask.Pix {
+		c.mask.Pix[i] = 0
+	}
+	c.raster.Draw(c.mask, c.mask.Bounds(), image.Opaque, image.Point{})
+
+	s := c.state()
+	if s.alpha < 1 {
+		for i, coverage := range c.mask.Pix {
+			c.mask.Pix[i] = uint8(float32(coverage)*clampF(s.alpha) + 0.5)
+		}
+	}
+	if s.clip != nil {
+		for i, coverage := range s.clip.Pix {
+			c.mask.Pix[i] = uint8((int(c.mask.Pix[i])*int(coverage) + 127) / 255)
+		}
+	}
+	r := s.scissor
+	draw.DrawMask(c.img, r, src, r.Min, c.mask, r.Min, draw.Over)
+}
+
+func (c *softCanvas) paintSource(p paint) image.Image {
+	var source softPaint
+	switch p.kind {
+	case paintImage:
+		img, ok := c.images[p.image]
+		if !ok {
+			return image.Transparent
+		}
+		source = softImagePattern{img: img, paint: p}
+	case paintBoxGradient:
+		source = softBoxGradient(p)
+	case paintLinearGradient:
+		source = softLinearGradient(p)
+	case paintRadialGradient:
+		source = softRadialGradient(p)
+	default:
+		return image.Transparent
+	}
+	// like in nanovgo the paint is transformed along with the path that it fills
+	return softPaintImage{paint: source, inverse: c.state().xform.inverse()}
+}
+
+// softPaint returns the color of a paint at a point in the coordinates the paint was created in
+type softPaint interface {
+	colorAt(x, y float64) color.RGBA64
+}
+
+// softPaintImage is a paint as an image, which is sampled in the middle of every pixel
+type softPaintImage struct {
+	paint   softPaint
+	inverse transform
+}
+
+func (p softPaintImage) ColorModel() color.Model { return color.RGBA64Model }
+
+func (p softPaintImage) Bounds() image.Rectangle {
+	return image.Rect(-1<<30, -1<<30, 1<<30, 1<<30)
+}
+
+func (p softPaintImage) At(x, y int) color.Color {
+	px, py := p.inverse.apply(float32(x)+0.5, float32(y)+0.5)
+	return p.paint.colorAt(float64(px), float64(py))
+}
+
+// softBoxGradient works like the box gradient shader of nanovgo, using the distance to the rounded rectangle
+type softBoxGradient paint
+
+func (p softBoxGradient) colorAt(x, y float64) color.RGBA64 {
+	// position relative to the center of the rectangle
+	px := x - float64(p.x+p.width/2)
+	py := y - float64(p.y+p.height/2)
+	radius := float64(p.radius)
+	dx := math.Abs(px) - (float64(p.width)/2 - radius)
+	dy := math.Abs(py) - (float64(p.height)/2 - radius)
+	distance := math.Min(math.Max(dx, dy), 0) + math.Hypot(math.Max(dx, 0), math.Max(dy, 0)) - radius
+
+	feather := math.Max(float64(p.feather), 1)
+	t := float32(math.Max(0, math.Min(1, (distance+feather/2)/feather)))
+	return mixColors(p.inner, p.outer, t)
+}
+
+// softLinearGradient mixes the colors by the position projected on the line from the start to the end
+type softLinearGradient paint
+
+func (p softLinearGradient) colorAt(x, y float64) color.RGBA64 {
+	dx, dy := float64(p.endX-p.x), float64(p.endY-p.y)
+	length := dx*dx + dy*dy
+	if length < 1e-6 {
+		// a hard stop, nanovgo treats it as a very short gradient
+		length = 1e-6
+	}
+	t := ((x-float64(p.x))*dx + (y-float64(p.y))*dy) / length
+	return mixColors(p.inner, p.outer, float32(math.Max(0, math.Min(1, t))))
+}
+
+// softRadialGradient mixes the colors by the distance to the center
+type softRadialGradient paint
+
+func (p softRadialGradient) colorAt(x, y float64) color.RGBA64 {
+	distance := math.Hypot(x-float64(p.x), y-float64(p.y))
+	width := math.Max(float64(p.radius-p.innerRadius), 1e-3)
+	t := (distance - float64(p.innerRadius)) / width
+	return mixColors(p.inner, p.outer, float32(math.Max(0, math.Min(1, t))))
+}
+
+// mixColors interpolates between two colors with premultiplied alpha
+func mixColors(a, b nanovgo.Color, t float32) color.RGBA64 {
+	alpha := clampF(a.A*(1-t) + b.A*t)
+	channel := func(ca, cb float32) uint16 {
+		return uint16(clampF(ca*a.A*(1-t)+cb*b.A*t) * 0xffff)
+	}
+	return color.RGBA64{
+		R: channel(a.R, b.R),
+		G: channel(a.G, b.G),
+		B: channel(a.B, b.B),
+		A: uint16(alpha * 0xffff),
+	}
+}
+
+// softImagePattern is an image stretched over the rectangle of the paint, the edge pixels are repeated outside of it
+type softImagePattern struct {
+	img   image.Image
+	paint paint
+}
+
+func (p softImagePattern) colorAt(x, y float64) color.RGBA64 {
+	b := p.img.Bounds()
+	u := (float32(x) - p.paint.x) / p.paint.width
+	v := (float32(y) - p.paint.y) / p.paint.height
+	imgX := b.Min.X + clampInt(int(u*float32(b.Dx())), 0, b.Dx()-1)
+	imgY := b.Min.Y + clampInt(int(v*float32(b.Dy())), 0, b.Dy()-1)
+
+	r, g, bl, a := p.img.At(imgX, imgY).RGBA()
+	alpha := p.paint.alpha
+	return color.RGBA64{
+		R: uint16(float32(r) * alpha),
+		G: uint16(float32(g) * alpha),
+		B: uint16(float32(bl) * alpha),
+		A: uint16(float32(a) * alpha),
+	}
+}
+
+func softColor(c nanovgo.Color) color.NRGBA {
+	return color.NRGBA{
+		R: uint8(clampF(c.R)*255 + 0.5),
+		G: uint8(clampF(c.G)*255 + 0.5),
+		B: uint8(clampF(c.B)*255 + 0.5),
+		A: uint8(clampF(c.A)*255 + 0.5),
+	}
+}
+
+func clampF(val float32) float32 {
+	return maxF(0, minF(val, 1))
+}
+
+func roundInt(val float32) int {
+	return int(math.Round(float64(val)))
+}
+
+func (c *softCanvas) CreateFontFromMemory(name string, data []byte, freeData uint8) int {
+	f, err := opentype.Parse(data)
+	if err != nil {
+		return -1
+	}
+	unitsPerEm := fixed.I(int(f.UnitsPerEm()))
+	metrics, err := f.Metrics(nil, unitsPerEm, font.HintingNone)
+	if err != nil {
+		return -1
+	}
+
+	c.fonts[name] = &softFont{
+		font:        f,
+		heightPerEm: float64(metrics.Ascent+metrics.Descent) / float64(unitsPerEm),
+		faces:       map[float32]font.Face{},
+	}
+	return len(c.fonts)
+}
+
+func (c *softCanvas) SetFontFace(font string) {
+	c.state().font = font
+}
+
+func (c *softCanvas) SetFontSize(size float32) {
+	c.state().fontSize = size
+}
+
+func (c *softCanvas) SetTextAlign(align nanovgo.Align) {
+	c.state().align = align
+}
+
+// face returns the current font at the current size, or nil if the font was never created
+func (c *softCanvas) face() font.Face {
+	s := c.state()
+	f, ok := c.fonts[s.font]
+	if !ok {
+		return nil
+	}
+	face, ok := f.faces[s.fontSize]
+	if !ok {
+		var err error
+		face, err = opentype.NewFace(f.font, &opentype.FaceOptions{
+			Size:    float64(s.fontSize) / f.heightPerEm,
+			DPI:     72,
+			Hinting: font.HintingNone,
+		})
+		if err != nil {
+			return nil
+		}
+		f.faces[s.fontSize] = face
+	}
+	return face
+}
+
+// glyphPositions returns where every rune starts, followed by the advance of the whole text
+func glyphPositions(face font.Face, runes []rune) []float32 {
+	positions := make([]float32, len(runes)+1)
+	x := fixed.Int26_6(0)
+	for i, r := range runes {
+		if i > 0 {
+			x += face.Kern(runes[i-1], r)
+		}
+		positions[i] = fromFixed(x)
+		advance, _ := face.GlyphAdvance(r)
+		x += advance
+	}
+	positions[len(runes)] = fromFixed(x)
+	return positions
+}
+
+// alignText returns the offset from the point given to the text functions to the start of the baseline
+func (c *softCanvas) alignText(face font.Face, width float32) (float32, float32) {
+	align := c.state().align
+	metrics := face.Metrics()
+	ascent, descent := fromFixed(metrics.Ascent), fromFixed(metrics.Descent)
+
+	dx, dy := float32(0), float32(0)
+	if align&nanovgo.AlignCenter != 0 {
+		dx = -width / 2
+	} else if align&nanovgo.AlignRight != 0 {
+		dx = -width
+	}
+	if align&nanovgo.AlignTop != 0 {
+		dy = ascent
+	} else if align&nanovgo.AlignMiddle != 0 {
+		dy = (ascent - descent) / 2
+	} else if align&nanovgo.AlignBottom != 0 {
+		dy = -descent
+	}
+	return dx, dy
+}
+
+func (c *softCanvas) Text(x, y float32, str string) float32 {
+	face := c.face()
+	if face == nil {
+		return x
+	}
+	positions := glyphPositions(face, []rune(str))
+	width := positions[len(positions)-1]
+	dx, dy := c.alignText(face, width)
+
+	s := c.state()
+	if s.xform[0] != 1 || s.xform[1] != 0 || s.xform[2] != 0 || s.xform[3] != 1 || s.clip != nil {
+		c.textPath(x+dx, y+dy, str, positions)
+		return x + dx + width
+	}
+
+	col := s.fillColor
+	col.A *= clampF(s.alpha)
+	dotX, dotY := s.xform.apply(x+dx, y+dy)
+	drawer := font.Drawer{
+		Dst:  c.img.SubImage(s.scissor).(*image.RGBA),
+		Src:  image.NewUniform(softColor(col)),
+		Face: face,
+		Dot:  fixed.Point26_6{X: toFixed(dotX), Y: toFixed(dotY)},
+	}
+	drawer.DrawString(str)
+	return x + dx + width
+}
+
+// textPath fills the outlines of the glyphs as a path, for text that is scaled, rotated or in a rotated scissor.
+// The baseline starts at x, y and the glyphs are placed at the given positions.
+func (c *softCanvas) textPath(x, y float32, str string, positions []float32) {
+	s := c.state()
+	f := c.fonts[s.font]
+	ppem := toFixed(s.fontSize / float32(f.heightPerEm))
+
+	// text doesn't change the current path in nanovgo
+	path := c.path
+	c.path = nil
+	defer func() { c.path = path }()
+
+	var buf sfnt.Buffer
+	for i, r := range []rune(str) {
+		index, err := f.font.GlyphIndex(&buf, r)
+		if err != nil || index == 0 {
+			continue
+		}
+		segments, err := f.font.LoadGlyph(&buf, index, ppem, nil)
+		if err != nil {
+			continue
+		}
+
+		glyphX := x + positions[i]
+		var lastX, lastY float32
+		for _, segment := range segments {
+			args := segment.Args
+			px, py := glyphX+fromFixed(args[0].X), y+fromFixed(args[0].Y)
+			switch segment.Op {
+			case sfnt.SegmentOpMoveTo:
+				c.MoveTo(px, py)
+			case sfnt.SegmentOpLineTo:
+				c.LineTo(px, py)
+			case sfnt.SegmentOpQuadTo:
+				// a quadratic curve as a cubic one
+				endX, endY := glyphX+fromFixed(args[1].X), y+fromFixed(args[1].Y)
+				c.BezierTo(lastX+(px-lastX)*2/3, lastY+(py-lastY)*2/3, endX+(px-endX)*2/3, endY+(py-endY)*2/3, endX, endY)
+				px, py = endX, endY
+			case sfnt.SegmentOpCubeTo:
+				endX, endY := glyphX+fromFixed(args[2].X), y+fromFixed(args[2].Y)
+				c.BezierTo(px, py, glyphX+fromFixed(args[1].X), y+fromFixed(args[1].Y), endX, endY)
+				px, py = endX, endY
+			}
+			lastX, lastY = px, py
+		}
+	}
+	c.fillPath(image.NewUniform(softColor(s.fillColor)), false)
+}
+
+func (c *softCanvas) TextBounds(x, y float32, str string) (float32, []float32) {
+	face := c.face()
+	if face == nil {
+		return 0, []float32{x, y, x, y}
+	}
+	positions := glyphPositions(face, []rune(str))
+	width := positions[len(positions)-1]
+	dx, dy := c.alignText(face, width)
+
+	metrics := face.Metrics()
+	return width, []float32{
+		x + dx,
+		y + dy - fromFixed(metrics.Ascent),
+		x + dx + width,
+		y + dy + fromFixed(metrics.Descent),
+	}
+}
+
+func (c *softCanvas) TextMetrics() (float32, float32, float32) {
+	face := c.face()
+	if face == nil {
+		return 0, 0, 0
+	}
+	metrics := face.Metrics()
+	return fromFixed(metrics.Ascent), -fromFixed(metrics.Descent), fromFixed(metrics.Height)
+}
+
+// TextBreakLines breaks the text at newlines and between words so that every row fits in breakRowWidth.
+// Words that are too long on their own are broken between characters.
+func (c *softCanvas) TextBreakLines(str string, breakRowWidth float32) []nanovgo.TextRow {
+	face := c.face()
+	if face == nil {
+		return nil
+	}
+	runes := []rune(str)
+	positions := glyphPositions(face, runes)
+
+	var rows []nanovgo.TextRow
+	for start := 0; start < len(runes); {
+		end, next := breakRow(runes, positions, start, breakRowWidth)
+		width := positions[end] - positions[start]
+		rows = append(rows, nanovgo.TextRow{
+			Runes:      runes,
+			StartIndex: start,
+			EndIndex:   end,
+			NextIndex:  next,
+			Width:      width,
+			MinX:       0,
+			MaxX:       width,
+		})
+		start = next
+	}
+	return rows
+}
+
+// breakRow returns where the row starting at start ends, without the trailing spaces, and where the next row starts
+func breakRow(runes []rune, positions []float32, start int, maxWidth float32) (int, int) {
+	wordEnd := -1 // end of the last word that fits
+	for i := start; i < len(runes); i++ {
+		if runes[i] == '\n' {
+			return i, i + 1
+		}
+		if runes[i] == ' ' {
+			if i > start && runes[i-1] != ' ' {
+				wordEnd = i
+			}
+			continue
+		}
+		if positions[i+1]-positions[start] <= maxWidth || i == start {
+			continue
+		}
+
+		if wordEnd < 0 {
+			return i, i
+		}
+		next := wordEnd
+		for next < len(runes) && runes[next] == ' ' {
+			next++
+		}
+		return wordEnd, next
+	}
+	return len(runes), len(runes)
+}
+
+func (c *softCanvas) TextGlyphPositions(x, y float32, str string) []nanovgo.GlyphPosition {
+	face := c.face()
+	if face == nil {
+		return nil
+	}
+	runes := []rune(str)
+	positions := glyphPositions(face, runes)
+	dx, _ := c.alignText(face, positions[len(positions)-1])
+
+	glyphs := make([]nanovgo.GlyphPosition, len(runes))
+	for i := range runes {
+		glyphs[i] = nanovgo.GlyphPosition{
+			Index: i,
+			Runes: runes,
+			X:     x + dx + positions[i],
+			MinX:  x + dx + positions[i],
+			MaxX:  x + dx + positions[i+1],
+		}
+	}
+	return glyphs
+}
+
+func fromFixed(val fixed.Int26_6) float32 {
+	return float32(val) / 64
+}
+
+func toFixed(val float32) fixed.Int26_6 {
+	return fixed.Int26_6(math.Round(float64(val) * 64))
+}
+
+func (c *softCanvas) CreateImageFromGoImage(imageFlag nanovgo.ImageFlags, img image.Image) int {
+	c.nextImage++
+	c.images[c.nextImage] = img
+	return c.nextImage
+}
+
+func (c *softCanvas) DeleteImage(img int) {
+	delete(c.images, img)
+}
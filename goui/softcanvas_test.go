@@ -0,0 +1,96 @@
+package goui
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shibukawa/nanovgo"
+)
+
+type pixel struct {
+	x, y   int
+	filled bool
+}
+
+func checkPixels(t *testing.T, c *softCanvas, pixels []pixel) {
+	t.Helper()
+	for _, p := range pixels {
+		if filled := c.img.RGBAAt(p.x, p.y).A > 128; filled != p.filled {
+			t.Errorf("pixel %v, %v filled: %v, expected %v", p.x, p.y, filled, p.filled)
+		}
+	}
+}
+
+func TestStrokeJoinsAndCaps(t *testing.T) {
+	c := newSoftCanvas(100, 100)
+	c.BeginPath()
+	// a right angle that is mitered, and a sharp turn that is beveled
+	c.MoveTo(10, 30)
+	c.LineTo(30, 30)
+	c.LineTo(30, 10)
+	c.MoveTo(40, 60)
+	c.LineTo(80, 60)
+	c.LineTo(40, 62)
+	c.SetStrokeWidth(10)
+	c.Stroke()
+
+	checkPixels(t, c, []pixel{
+		{20, 30, true},
+		{33, 33, true},  // outside corner of the miter
+		{8, 30, false},  // before the butt cap at the start
+		{30, 8, false},  // after the butt cap at the end
+		{85, 61, false}, // where a miter of the sharp turn would end
+	})
+}
+
+func TestStrokeClosedPath(t *testing.T) {
+	c := newSoftCanvas(100, 100)
+	c.BeginPath()
+	c.Rect(20, 20, 60, 60)
+	c.SetStrokeWidth(6)
+	c.Stroke()
+
+	checkPixels(t, c, []pixel{
+		{17, 17, true}, // the corner where the path is closed is joined too
+		{82, 17, true},
+		{82, 82, true},
+		{17, 82, true},
+		{50, 50, false},
+	})
+}
+
+func TestRotatedScissor(t *testing.T) {
+	c := newSoftCanvas(100, 100)
+	c.Save()
+	c.Translate(50, 50)
+	c.Rotate(math.Pi / 4)
+	c.IntersectScissor(-20, -20, 40, 40)
+	c.BeginPath()
+	c.Rect(-50, -50, 100, 100)
+	c.SetFillColor(nanovgo.RGBA(0, 0, 0, 255))
+	c.Fill()
+	c.Restore()
+
+	// the scissor is a diamond, the corners of its bounding box are not drawn
+	checkPixels(t, c, []pixel{
+		{50, 50, true},
+		{50, 75, true},
+		{75, 50, true},
+		{68, 68, false},
+		{32, 32, false},
+	})
+
+	c.clear()
+	c.BeginPath()
+	c.Rect(0, 0, 100, 100)
+	c.Fill()
+	checkPixels(t, c, []pixel{{5, 5, true}})
+}
+
+func TestFillSmallCircle(t *testing.T) {
+	c := newSoftCanvas(10, 10)
+	c.BeginPath()
+	c.Circle(5, 5, 1)
+	c.Fill()
+	checkPixels(t, c, []pixel{{4, 4, true}, {5, 5, true}, {7, 7, false}})
+}
@@ -0,0 +1,109 @@
+package goui
+
+import (
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// stateStore keeps values between renders. Values that are not used during a render are removed.
+type stateStore struct {
+	values map[string]interface{}
+	used   map[string]bool
+}
+
+func newStateStore() *stateStore {
+	return &stateStore{
+		values: map[string]interface{}{},
+		used:   map[string]bool{},
+	}
+}
+
+func (s *stateStore) get(key string, init func() interface{}) interface{} {
+	s.used[key] = true
+	value, ok := s.values[key]
+	if !ok {
+		value = init()
+		s.values[key] = value
+	}
+	return value
+}
+
+// collect removes all values that have not been used since the last call to collect
+func (s *stateStore) collect() {
+	for key := range s.values {
+		if !s.used[key] {
+			delete(s.values, key)
+		}
+	}
+	s.used = map[string]bool{}
+}
+
+// State returns the value stored under key for the box it is called in, calling init to create it the first time.
+// Every box has its own values, so sibling boxes, like the items of a list or two instances of the same component,
+// don't share them. The value is kept between renders for as long as the box is rendered and calls State with
+// the key, so store a pointer to be able to change it. Outside of a box, the value belongs to the whole UI.
+//
+// Boxes are identified by where in the code they are created. To keep the values of boxes that are added,
+// removed or reordered, create them with KeyedBox. A key given with Handle.Key is only known after the
+// children of the box are created, so it doesn't identify the values of the box.
+func (g *gui) State(key string, init func() interface{}) interface{} {
+	return g.states.get(g.currentBox.stateID+":state:"+key, init)
+}
+
+// widgetState returns state that the framework keeps for a widget, like the scroll offset of a box
+func (g *gui) widgetState(w *widgetContainer, name string, init func() interface{}) interface{} {
+	return g.states.get(w.id+":"+name, init)
+}
+
+var packagePrefix = reflect.TypeOf(gui{}).PkgPath() + "."
+
+// callSite returns the location in the user code that created a widget. It is the function and line
+// rather than the program counter, which differs for every place a function is inlined.
+func callSite() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, packagePrefix) || !more {
+			return frame.Function + ":" + strconv.Itoa(frame.Line)
+		}
+	}
+}
+
+// stateID returns the id of a new child of the box for State. It is built from the key if the child is
+// created with one, or the call site and how many siblings before it were created at the same call site.
+func stateID(parentID string, box *boxWidget, callSite string, key string) string {
+	if key != "" {
+		return parentID + "/#" + key
+	}
+	if box.callSites == nil {
+		box.callSites = map[string]int{}
+	}
+	index := box.callSites[callSite]
+	box.callSites[callSite]++
+	return parentID + "/" + callSite + ":" + strconv.Itoa(index)
+}
+
+// assignIDs gives every widget an id that stays the same between renders. The id is built from the ids of
+// the parents and either the key given with Handle.Key, or the call site and how many siblings before it
+// were created at the same call site.
+func assignIDs(w *widgetContainer) {
+	box, ok := w.widget.(*boxWidget)
+	if !ok {
+		return
+	}
+
+	seen := map[string]int{}
+	for _, child := range box.children {
+		if child.handle.key != "" {
+			child.id = w.id + "/#" + child.handle.key
+		} else {
+			child.id = w.id + "/" + child.callSite + ":" + strconv.Itoa(seen[child.callSite])
+			seen[child.callSite]++
+		}
+		assignIDs(child)
+	}
+}
@@ -0,0 +1,146 @@
+package goui_test
+
+import (
+	"testing"
+
+	"../goui"
+)
+
+// the tests are outside of the goui package, so that the widgets are identified by the call sites in the tests
+
+type stateDriver struct {
+	*goui.Driver
+	ui goui.UI
+}
+
+func newStateDriver(t *testing.T, render func(g goui.UI)) *stateDriver {
+	t.Helper()
+	d := &stateDriver{}
+	driver, err := goui.NewDriver(100, 100, func(g goui.UI) {
+		d.ui = g
+		render(g)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Driver = driver
+	return d
+}
+
+func (d *stateDriver) render() {
+	d.ui.Rerender()
+	d.Step()
+}
+
+func count(g goui.UI) *int {
+	return g.State("count", func() interface{} { return new(int) }).(*int)
+}
+
+// counter is a component that keeps a count in the state of its box
+func counter(g goui.UI, counts map[string]*int, name string) {
+	g.Box(func() {
+		counts[name] = count(g)
+	})
+}
+
+func TestStateOfComponents(t *testing.T) {
+	counts := map[string]*int{}
+	showFirst := true
+	d := newStateDriver(t, func(g goui.UI) {
+		if showFirst {
+			counter(g, counts, "first")
+		}
+		counter(g, counts, "second")
+	})
+	if counts["first"] == counts["second"] {
+		t.Fatal("two instances of a component share their state")
+	}
+	*counts["first"], *counts["second"] = 1, 2
+
+	d.render()
+	if *counts["first"] != 1 || *counts["second"] != 2 {
+		t.Fatalf("state was not kept: %v %v", *counts["first"], *counts["second"])
+	}
+
+	// without the first instance, the second one takes its position and state
+	showFirst = false
+	d.render()
+	if *counts["second"] != 1 {
+		t.Errorf("instances are identified by their position: %v", *counts["second"])
+	}
+	showFirst = true
+	d.render()
+	if *counts["first"] != 1 || *counts["second"] != 0 {
+		t.Errorf("instances are identified by their position: %v %v", *counts["first"], *counts["second"])
+	}
+}
+
+func TestStateOfCallSites(t *testing.T) {
+	var a, b *int
+	showA := true
+	d := newStateDriver(t, func(g goui.UI) {
+		if showA {
+			g.Box(func() { a = count(g) })
+		}
+		g.Box(func() { b = count(g) })
+	})
+	if a == b {
+		t.Fatal("boxes created at different call sites share their state")
+	}
+	*a, *b = 1, 2
+
+	showA = false
+	d.render()
+	if *b != 2 {
+		t.Errorf("state moved when a box created before it was removed: %v", *b)
+	}
+
+	showA = true
+	d.render()
+	if *a != 0 || *b != 2 {
+		t.Errorf("state of a box that was not rendered was kept: a=%v b=%v", *a, *b)
+	}
+}
+
+func TestStateOfKeyedBoxes(t *testing.T) {
+	counts := map[string]*int{}
+	items := []string{"a", "b", "c"}
+	d := newStateDriver(t, func(g goui.UI) {
+		for _, item := range items {
+			item := item
+			g.KeyedBox(item, func() { counts[item] = count(g) })
+		}
+	})
+	*counts["a"], *counts["b"], *counts["c"] = 1, 2, 3
+
+	items = []string{"d", "c", "a"}
+	d.render()
+	if *counts["a"] != 1 || *counts["c"] != 3 || *counts["d"] != 0 {
+		t.Fatalf("reordered boxes got the wrong state: a=%v c=%v d=%v", *counts["a"], *counts["c"], *counts["d"])
+	}
+	if _, ok := d.Find("c"); !ok {
+		t.Error("the key of a KeyedBox doesn't identify the widget")
+	}
+
+	items = []string{"b"}
+	d.render()
+	if *counts["b"] != 0 {
+		t.Errorf("state of a removed box was kept: %v", *counts["b"])
+	}
+}
+
+func TestStateOutsideOfBoxes(t *testing.T) {
+	var first, second *int
+	d := newStateDriver(t, func(g goui.UI) {
+		first = count(g)
+		second = count(g)
+	})
+	if first != second {
+		t.Error("state outside of boxes is not shared by the whole UI")
+	}
+	*first = 1
+	d.render()
+	if *second != 1 {
+		t.Errorf("state outside of boxes was not kept: %v", *second)
+	}
+}
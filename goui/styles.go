@@ -2,9 +2,9 @@ package goui
 
 import (
 	"image/color"
+	"time"
 
 	"github.com/kjk/flex"
-	"github.com/shibukawa/nanovgo"
 )
 
 const unset = -1
@@ -36,10 +36,36 @@ type Styles struct {
 	fontSize     float64
 	textAlign    TextAlign
 	textBaseline TextBaseline
-
-	color        color.Color
-	background   color.Color
-	borderRadius float64
+	lineHeight   float64
+	maxLines     int
+	whiteSpace   WhiteSpace
+	initial      Inherited // inherited styles that are reset to their initial value, see Initial
+
+	color           color.Color
+	background      color.Color
+	backgroundPaint *backgroundPaint // nil when unset
+	borderRadius    corners
+	borderWidth     edges
+	borderColor     edgeColors
+	borderStyle     edgeBorderStyles
+	boxShadows      []boxShadow // nil when unset, empty to remove the shadows
+
+	objectFit ObjectFit
+
+	opacity     float64
+	transform   transformStyles
+	transitions []transition // nil when unset
+
+	focusRingWidth float64
+	focusRingColor color.Color
+
+	theme *Theme // set when the styles are applied to a widget, for the colors the widgets draw with
+
+	// variants used when the widget is in a specific state, see resolve
+	hover    *Styles
+	active   *Styles
+	focus    *Styles
+	disabled *Styles
 }
 
 const defaultPadding = 0
@@ -47,9 +73,6 @@ const defaultMargin = 0
 const defaultFontSize = 18
 const defaultFont = FontRegular
 
-var defaultColor = color.RGBA{R: 255, G: 255, B: 255, A: 255}
-var defaultBackground = color.RGBA{}
-
 func NewStyles() Styles {
 	return Styles{
 		width:     size{unit: unset},
@@ -77,10 +100,23 @@ func NewStyles() Styles {
 		fontSize:     unset, //18,
 		textAlign:    unset, //TextLeft,
 		textBaseline: unset, //TextMiddle,
+		lineHeight:   unset, //1.2,
+		maxLines:     unset,
+		whiteSpace:   unset, //WhiteSpaceWrap,
 
 		color:        nil, // color.RGBA{R: 255, G: 255, B: 255, A: 255},
 		background:   nil, // color.RGBA{},
-		borderRadius: unset,
+		borderRadius: corners{unset, unset, unset, unset},
+		borderWidth:  edges{unset, unset, unset, unset},
+		borderColor:  edgeColors{nil, nil, nil, nil}, // defaultColor
+		borderStyle:  edgeBorderStyles{unset, unset, unset, unset},
+
+		objectFit: unset, //ObjectFitFill,
+
+		opacity: unset, //1,
+
+		focusRingWidth: unset, //2,
+		focusRingColor: nil,   //color.RGBA{R: 90, G: 160, B: 255, A: 255},
 	}
 }
 
@@ -93,7 +129,7 @@ func getFontSize(size float64) float64 {
 
 func getFontFamily(path string) string {
 	if path == "" {
-		return defaultFont
+		path = defaultFont
 	}
 	return path
 }
@@ -135,22 +171,204 @@ func (h Styles) FlexGrow(grow float64) Styles                 { h.flexGrow = gro
 func (h Styles) FlexShrink(shrink float64) Styles             { h.flexShrink = shrink; return h }
 func (h Styles) AlignSelf(align Align) Styles                 { h.alignSelf = align; return h }
 
-func (h Styles) FontFamily(path string) Styles             { h.fontFamily = path; return h }
-func (h Styles) FontSize(px float64) Styles                { h.fontSize = px; return h }
-func (h Styles) TextAlign(align TextAlign) Styles          { h.textAlign = align; return h }
+func (h Styles) FontFamily(path string) Styles             { h.fontFamily = path; h.initial &^= InheritedFontFamily; return h }
+func (h Styles) FontSize(px float64) Styles                { h.fontSize = px; h.initial &^= InheritedFontSize; return h }
+func (h Styles) TextAlign(align TextAlign) Styles          { h.textAlign = align; h.initial &^= InheritedTextAlign; return h }
 func (h Styles) TextBaseline(baseline TextBaseline) Styles { h.textBaseline = baseline; return h }
+func (h Styles) LineHeight(multiplier float64) Styles      { h.lineHeight = multiplier; return h }
+func (h Styles) MaxLines(lines int) Styles                 { h.maxLines = lines; return h }
+func (h Styles) WhiteSpace(whiteSpace WhiteSpace) Styles   { h.whiteSpace = whiteSpace; return h }
+
+// Font sets the family and size, and the line height if it is set, of a font of the theme
+func (h Styles) Font(font Font) Styles {
+	h.fontFamily = font.Family
+	h.fontSize = font.Size
+	if font.LineHeight > 0 {
+		h.lineHeight = font.LineHeight
+	}
+	return h
+}
+
+// Initial resets inherited styles to their initial value instead of taking them from the parent box,
+// like the css initial keyword. Setting the style again afterwards replaces the initial value.
+func (h Styles) Initial(styles Inherited) Styles {
+	h = h.reset(styles)
+	h.initial |= styles
+	return h
+}
 
-func (h Styles) Color(color color.Color) Styles      { h.color = color; return h }
+func (h Styles) Color(color color.Color) Styles      { h.color = color; h.initial &^= InheritedColor; return h }
 func (h Styles) Background(color color.Color) Styles { h.background = color; return h }
-func (h Styles) BorderRadius(px float64) Styles      { h.borderRadius = px; return h }
-
-func applyStyles(ctx *nanovgo.Context, widget widget, l *flex.Node, s Styles) {
-	if wid, ok := widget.(*textWidget); ok {
-		//ctx.cv.SetFont(getFontFamily(s.fontFamily), getFontSize(s.fontSize))
-		//textWidth := ctx.cv.MeasureText(wid.text).Width
-		//l.StyleSetMinWidth(float32(textWidth))
-		l.StyleSetMinHeight(float32(getFontSize(s.fontSize)))
-		_ = wid
+
+// LinearGradient draws a gradient over the background color. Like in css the angle is in degrees
+// clockwise from the top, so 0 goes from the bottom to the top and 90 from the left to the right.
+func (h Styles) LinearGradient(angle float64, stops ...GradientStop) Styles {
+	h.backgroundPaint = &backgroundPaint{kind: backgroundLinear, angle: angle, stops: stops}
+	return h
+}
+
+// RadialGradient draws a circular gradient from the middle of the widget, with offset 1 at the corners
+func (h Styles) RadialGradient(stops ...GradientStop) Styles {
+	h.backgroundPaint = &backgroundPaint{kind: backgroundRadial, stops: stops}
+	return h
+}
+
+// BoxGradient draws the inner color in the middle of the widget that fades to the outer color
+// at the edges, following the rounded corners, over the given distance
+func (h Styles) BoxGradient(feather float64, inner, outer color.Color) Styles {
+	h.backgroundPaint = &backgroundPaint{kind: backgroundBox, feather: feather, stops: []GradientStop{{0, inner}, {1, outer}}}
+	return h
+}
+
+// BackgroundImage draws the image at path over the background color, scaled to cover the widget
+func (h Styles) BackgroundImage(path string) Styles {
+	h.backgroundPaint = &backgroundPaint{kind: backgroundImage, path: path}
+	return h
+}
+
+// NoBackgroundPaint removes gradients and images, also the ones of styles that this is combined on top of
+func (h Styles) NoBackgroundPaint() Styles {
+	h.backgroundPaint = &backgroundPaint{kind: backgroundNone}
+	return h
+}
+
+func (h Styles) BorderRadius(px float64) Styles {
+	h.borderRadius = h.borderRadius.apply(CornerAll, px)
+	return h
+}
+func (h Styles) CornerRadius(corner Corner, px float64) Styles {
+	h.borderRadius = h.borderRadius.apply(corner, px)
+	return h
+}
+
+// Border sets the width and color of the border, which is drawn inside the size of the widget like
+// in css with box-sizing: border-box. The content is moved inside the border.
+func (h Styles) Border(edge Edge, px float64, color color.Color) Styles {
+	h.borderWidth = h.borderWidth.apply(edge, px)
+	h.borderColor = h.borderColor.apply(edge, color)
+	return h
+}
+func (h Styles) BorderWidth(edge Edge, px float64) Styles {
+	h.borderWidth = h.borderWidth.apply(edge, px)
+	return h
+}
+func (h Styles) BorderColor(edge Edge, color color.Color) Styles {
+	h.borderColor = h.borderColor.apply(edge, color)
+	return h
+}
+func (h Styles) BorderStyle(edge Edge, style BorderStyle) Styles {
+	h.borderStyle = h.borderStyle.apply(edge, style)
+	return h
+}
+
+// BoxShadow adds a shadow under the widget, like the css box-shadow property. Call it again to add more
+// shadows, the first one is drawn on top. Combining styles with shadows replaces all the shadows.
+func (h Styles) BoxShadow(offsetX, offsetY, blur, spread float64, color color.Color) Styles {
+	h.boxShadows = appendShadow(h.boxShadows, boxShadow{offsetX, offsetY, blur, spread, color, false})
+	return h
+}
+
+// InsetBoxShadow adds a shadow inside the widget, above the background and below the border
+func (h Styles) InsetBoxShadow(offsetX, offsetY, blur, spread float64, color color.Color) Styles {
+	h.boxShadows = appendShadow(h.boxShadows, boxShadow{offsetX, offsetY, blur, spread, color, true})
+	return h
+}
+
+// NoBoxShadow removes the shadows, also the ones of styles that this is combined on top of
+func (h Styles) NoBoxShadow() Styles {
+	h.boxShadows = []boxShadow{}
+	return h
+}
+
+func (h Styles) ObjectFit(fit ObjectFit) Styles { h.objectFit = fit; return h }
+
+// Opacity makes the widget and its children transparent, from 0 for invisible to 1 for opaque.
+// Transparent widgets can still be clicked, like in css.
+func (h Styles) Opacity(alpha float64) Styles { h.opacity = alpha; return h }
+
+// Translate moves the widget and its children when they are drawn, without changing the layout
+func (h Styles) Translate(x, y float64) Styles {
+	h.transform.translateX, h.transform.translateY = x, y
+	h.transform.set |= transformTranslate
+	return h
+}
+
+// Scale scales the widget and its children around the transform origin when they are drawn
+func (h Styles) Scale(x, y float64) Styles {
+	h.transform.scaleX, h.transform.scaleY = x, y
+	h.transform.set |= transformScale
+	return h
+}
+
+// Rotate rotates the widget and its children clockwise around the transform origin, by the angle in degrees
+func (h Styles) Rotate(degrees float64) Styles {
+	h.transform.rotate = degrees
+	h.transform.set |= transformRotate
+	return h
+}
+
+// Transition animates changes of the property between renders over the duration, instead of changing it at once.
+// Call it again to animate more properties. It only works for widgets with a Key, since other widgets
+// can't always be told apart between renders. A nil easing uses Ease.
+func (h Styles) Transition(property Property, duration time.Duration, easing Easing) Styles {
+	h.transitions = append(append([]transition{}, h.transitions...), transition{property, duration, easing})
+	return h
+}
+
+// TransformOrigin sets the point to scale and rotate around, in percent of the size of the widget.
+// It is in the middle by default.
+func (h Styles) TransformOrigin(xPct, yPct float64) Styles {
+	h.transform.originX, h.transform.originY = xPct, yPct
+	h.transform.set |= transformOrigin
+	return h
+}
+
+// FocusRing sets the outline drawn around the widget when it has the keyboard focus, a width of 0 hides it
+func (h Styles) FocusRing(px float64, color color.Color) Styles {
+	h.focusRingWidth = px
+	h.focusRingColor = color
+	return h
+}
+
+// Hover, Active, Focus and Disabled set styles that are combined on top when the widget is in that state,
+// like the css pseudo-classes. If several states apply they are combined in the order hover, focus, active,
+// disabled, so that pressing a focused widget shows its active styles.
+func (h Styles) Hover(styles Styles) Styles    { h.hover = &styles; return h }
+func (h Styles) Active(styles Styles) Styles   { h.active = &styles; return h }
+func (h Styles) Focus(styles Styles) Styles    { h.focus = &styles; return h }
+func (h Styles) Disabled(styles Styles) Styles { h.disabled = &styles; return h }
+
+// resolve combines the variants matching the state of a widget on top of the styles
+func (h Styles) resolve(state widgetState) Styles {
+	variants := []struct {
+		state  widgetState
+		styles *Styles
+	}{
+		{stateHover, h.hover},
+		{stateFocus, h.focus},
+		{stateActive, h.active},
+		{stateDisabled, h.disabled},
+	}
+
+	resolved := h
+	for _, variant := range variants {
+		if state&variant.state != 0 && variant.styles != nil {
+			resolved = CombineStyles(resolved, *variant.styles)
+		}
+	}
+	return resolved
+}
+
+func applyStyles(ctx canvas, widget widget, l *flex.Node, s Styles) {
+	switch wid := widget.(type) {
+	case *textWidget:
+		l.SetMeasureFunc(textMeasureFunc(ctx, wid.text, s))
+	case *buttonWidget:
+		l.SetMeasureFunc(textMeasureFunc(ctx, wid.text, s))
+	case *imageWidget:
+		l.SetMeasureFunc(imageMeasureFunc(float32(wid.res.width), float32(wid.res.height)))
+	case *inputWidget:
+		l.SetMeasureFunc(inputMeasureFunc(ctx, s))
 	}
 
 	setLayoutSize(s.width, l.StyleSetWidth, l.StyleSetWidthPercent)
@@ -162,6 +380,7 @@ func applyStyles(ctx *nanovgo.Context, widget widget, l *flex.Node, s Styles) {
 
 	setLayoutEdges(s.margin, defaultMargin, l.StyleSetMargin)
 	setLayoutEdges(s.padding, defaultPadding, l.StyleSetPadding)
+	setLayoutEdges(s.borderWidth, 0, l.StyleSetBorder)
 
 	l.StyleSetPositionType(flex.PositionType(checkUnset(int(s.position), int(flex.PositionTypeRelative))))
 	l.StyleSetOverflow(flex.Overflow(checkUnset(int(s.overflow), int(flex.OverflowVisible))))
@@ -215,6 +434,9 @@ func CombineStyles(styles ...Styles) Styles {
 	style := styles[0]
 
 	for _, s := range styles[1:] {
+		style = style.reset(s.initial)
+		style.initial = (style.initial | s.initial) &^ s.setInherited()
+
 		if s.width.unit != unset {
 			style.width = s.width
 		}
@@ -306,6 +528,15 @@ func CombineStyles(styles ...Styles) Styles {
 		if s.textBaseline != unset {
 			style.textBaseline = s.textBaseline
 		}
+		if s.lineHeight != unset {
+			style.lineHeight = s.lineHeight
+		}
+		if s.maxLines != unset {
+			style.maxLines = s.maxLines
+		}
+		if s.whiteSpace != unset {
+			style.whiteSpace = s.whiteSpace
+		}
 
 		if s.color != nil {
 			style.color = s.color
@@ -313,14 +544,103 @@ func CombineStyles(styles ...Styles) Styles {
 		if s.background != nil {
 			style.background = s.background
 		}
-		if s.borderRadius != unset {
-			style.borderRadius = s.borderRadius
+		if s.borderRadius.topLeft != unset {
+			style.borderRadius.topLeft = s.borderRadius.topLeft
+		}
+		if s.borderRadius.topRight != unset {
+			style.borderRadius.topRight = s.borderRadius.topRight
+		}
+		if s.borderRadius.bottomRight != unset {
+			style.borderRadius.bottomRight = s.borderRadius.bottomRight
+		}
+		if s.borderRadius.bottomLeft != unset {
+			style.borderRadius.bottomLeft = s.borderRadius.bottomLeft
+		}
+
+		if s.borderWidth.top != unset {
+			style.borderWidth.top = s.borderWidth.top
+		}
+		if s.borderWidth.right != unset {
+			style.borderWidth.right = s.borderWidth.right
+		}
+		if s.borderWidth.bottom != unset {
+			style.borderWidth.bottom = s.borderWidth.bottom
+		}
+		if s.borderWidth.left != unset {
+			style.borderWidth.left = s.borderWidth.left
+		}
+
+		if s.borderColor.top != nil {
+			style.borderColor.top = s.borderColor.top
+		}
+		if s.borderColor.right != nil {
+			style.borderColor.right = s.borderColor.right
+		}
+		if s.borderColor.bottom != nil {
+			style.borderColor.bottom = s.borderColor.bottom
+		}
+		if s.borderColor.left != nil {
+			style.borderColor.left = s.borderColor.left
+		}
+
+		if s.backgroundPaint != nil {
+			style.backgroundPaint = s.backgroundPaint
+		}
+		if s.boxShadows != nil {
+			style.boxShadows = s.boxShadows
+		}
+
+		if s.borderStyle.top != unset {
+			style.borderStyle.top = s.borderStyle.top
+		}
+		if s.borderStyle.right != unset {
+			style.borderStyle.right = s.borderStyle.right
+		}
+		if s.borderStyle.bottom != unset {
+			style.borderStyle.bottom = s.borderStyle.bottom
+		}
+		if s.borderStyle.left != unset {
+			style.borderStyle.left = s.borderStyle.left
+		}
+
+		if s.objectFit != unset {
+			style.objectFit = s.objectFit
+		}
+		if s.opacity != unset {
+			style.opacity = s.opacity
 		}
+		style.transform = style.transform.combine(s.transform)
+		if s.transitions != nil {
+			style.transitions = s.transitions
+		}
+
+		if s.focusRingWidth != unset {
+			style.focusRingWidth = s.focusRingWidth
+		}
+		if s.focusRingColor != nil {
+			style.focusRingColor = s.focusRingColor
+		}
+
+		style.hover = combineVariant(style.hover, s.hover)
+		style.active = combineVariant(style.active, s.active)
+		style.focus = combineVariant(style.focus, s.focus)
+		style.disabled = combineVariant(style.disabled, s.disabled)
 	}
 
 	return style
 }
 
+func combineVariant(a, b *Styles) *Styles {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	combined := CombineStyles(*a, *b)
+	return &combined
+}
+
 type Edge int
 
 const (
@@ -386,6 +706,122 @@ func (e edges) apply(edge Edge, px float64) edges {
 	return e
 }
 
+type edgeColors struct {
+	top, right, bottom, left color.Color
+}
+
+func (e edgeColors) apply(edge Edge, color color.Color) edgeColors {
+	switch edge {
+	case EdgeAll:
+		e.top = color
+		e.right = color
+		e.bottom = color
+		e.left = color
+	case EdgeVertical:
+		e.top = color
+		e.bottom = color
+	case EdgeHorizontal:
+		e.left = color
+		e.right = color
+	case EdgeTop:
+		e.top = color
+	case EdgeRight:
+		e.right = color
+	case EdgeBottom:
+		e.bottom = color
+	case EdgeLeft:
+		e.left = color
+	}
+	return e
+}
+
+type edgeBorderStyles struct {
+	top, right, bottom, left BorderStyle
+}
+
+func (e edgeBorderStyles) apply(edge Edge, style BorderStyle) edgeBorderStyles {
+	switch edge {
+	case EdgeAll:
+		e.top = style
+		e.right = style
+		e.bottom = style
+		e.left = style
+	case EdgeVertical:
+		e.top = style
+		e.bottom = style
+	case EdgeHorizontal:
+		e.left = style
+		e.right = style
+	case EdgeTop:
+		e.top = style
+	case EdgeRight:
+		e.right = style
+	case EdgeBottom:
+		e.bottom = style
+	case EdgeLeft:
+		e.left = style
+	}
+	return e
+}
+
+type Corner int
+
+const (
+	CornerAll Corner = iota
+	CornerTop
+	CornerRight
+	CornerBottom
+	CornerLeft
+	CornerTopLeft
+	CornerTopRight
+	CornerBottomRight
+	CornerBottomLeft
+)
+
+type corners struct {
+	topLeft, topRight, bottomRight, bottomLeft float64
+}
+
+func (c corners) apply(corner Corner, px float64) corners {
+	switch corner {
+	case CornerAll:
+		c.topLeft = px
+		c.topRight = px
+		c.bottomRight = px
+		c.bottomLeft = px
+	case CornerTop:
+		c.topLeft = px
+		c.topRight = px
+	case CornerRight:
+		c.topRight = px
+		c.bottomRight = px
+	case CornerBottom:
+		c.bottomRight = px
+		c.bottomLeft = px
+	case CornerLeft:
+		c.topLeft = px
+		c.bottomLeft = px
+	case CornerTopLeft:
+		c.topLeft = px
+	case CornerTopRight:
+		c.topRight = px
+	case CornerBottomRight:
+		c.bottomRight = px
+	case CornerBottomLeft:
+		c.bottomLeft = px
+	}
+	return c
+}
+
+// BorderStyle is how the border is drawn, like the css border-style property
+type BorderStyle int
+
+const (
+	BorderSolid BorderStyle = iota
+	BorderDashed
+	BorderDotted
+)
+
 type TextAlign int
 
 const (
@@ -402,6 +838,24 @@ const (
 	TextBottom
 )
 
+type WhiteSpace int
+
+const (
+	WhiteSpaceWrap   WhiteSpace = iota // collapse spaces, keep newlines and wrap lines at the layout width
+	WhiteSpaceNoWrap                   // collapse spaces and newlines into a single line
+	WhiteSpacePre                      // keep all white space and only break lines at newlines
+)
+
+// ObjectFit decides how an image is resized to fit its box, like the css object-fit property
+type ObjectFit int
+
+const (
+	ObjectFitFill    ObjectFit = iota // stretch the image to fill the box
+	ObjectFitContain                  // scale the image to fit inside the box, keeping the aspect ratio
+	ObjectFitCover                    // scale the image to cover the whole box, keeping the aspect ratio
+	ObjectFitNone                     // keep the original size of the image
+)
+
 // Copied from https://github.com/kjk/flex/blob/ed34d6b6a425cc6c1b76e224b0c882d608c12aaa/enums.go
 
 type Align int
@@ -487,6 +941,50 @@ func (value TextBaseline) String() string {
 	return "unknown"
 }
 
+func (value WhiteSpace) String() string {
+	switch value {
+	case unset:
+		return "unset"
+	case WhiteSpaceWrap:
+		return "wrap"
+	case WhiteSpaceNoWrap:
+		return "no-wrap"
+	case WhiteSpacePre:
+		return "pre"
+	}
+	return "unknown"
+}
+
+func (value ObjectFit) String() string {
+	switch value {
+	case unset:
+		return "unset"
+	case ObjectFitFill:
+		return "fill"
+	case ObjectFitContain:
+		return "contain"
+	case ObjectFitCover:
+		return "cover"
+	case ObjectFitNone:
+		return "none"
+	}
+	return "unknown"
+}
+
+func (value BorderStyle) String() string {
+	switch value {
+	case unset:
+		return "unset"
+	case BorderSolid:
+		return "solid"
+	case BorderDashed:
+		return "dashed"
+	case BorderDotted:
+		return "dotted"
+	}
+	return "unknown"
+}
+
 func (value Align) String() string {
 	switch value {
 	case unset:
@@ -0,0 +1,34 @@
+package goui
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestResolveVariantOrder(t *testing.T) {
+	hover := color.RGBA{R: 1, A: 255}
+	focus := color.RGBA{G: 1, A: 255}
+	active := color.RGBA{B: 1, A: 255}
+	disabled := color.RGBA{R: 1, G: 1, A: 255}
+	s := NewStyles().
+		Hover(NewStyles().Background(hover)).
+		Focus(NewStyles().Background(focus)).
+		Active(NewStyles().Background(active)).
+		Disabled(NewStyles().Background(disabled))
+
+	tests := []struct {
+		state widgetState
+		want  color.Color
+	}{
+		{stateHover, hover},
+		{stateHover | stateFocus, focus},
+		{stateFocus | stateActive, active},
+		{stateHover | stateFocus | stateActive, active},
+		{stateFocus | stateActive | stateDisabled, disabled},
+	}
+	for _, test := range tests {
+		if got := s.resolve(test.state).background; got != test.want {
+			t.Errorf("state %b: background %v, expected %v", test.state, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package goui
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/kjk/flex"
+	"github.com/shibukawa/nanovgo"
+)
+
+const defaultLineHeight = 1.2
+
+func setFont(ctx canvas, s Styles) {
+	ctx.SetFontFace(getFontFamily(s.fontFamily))
+	ctx.SetFontSize(float32(getFontSize(s.fontSize)))
+}
+
+type textLine struct {
+	text  string
+	width float32
+}
+
+type textLayout struct {
+	lines      []textLine
+	lineHeight float32 // height of every line, including the line spacing
+	fontHeight float32 // height of the font itself
+}
+
+func (t textLayout) size() (float32, float32) {
+	width := float32(0)
+	for _, line := range t.lines {
+		if line.width > width {
+			width = line.width
+		}
+	}
+	return width, float32(len(t.lines)) * t.lineHeight
+}
+
+// layoutText splits the text into lines, breaking them at maxWidth if the white space style allows it.
+// Pass an infinite maxWidth to only break at newlines.
+func layoutText(ctx canvas, text string, s Styles, maxWidth float32) textLayout {
+	setFont(ctx, s)
+	ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignTop)
+	_, _, fontHeight := ctx.TextMetrics()
+
+	layout := textLayout{
+		fontHeight: fontHeight,
+		lineHeight: fontHeight * float32(checkUnsetF(s.lineHeight, defaultLineHeight)),
+	}
+
+	whiteSpace := WhiteSpace(checkUnset(int(s.whiteSpace), int(WhiteSpaceWrap)))
+	text = collapseWhiteSpace(text, whiteSpace)
+
+	if whiteSpace == WhiteSpaceWrap && !math.IsInf(float64(maxWidth), 1) && !math.IsNaN(float64(maxWidth)) {
+		for _, row := range ctx.TextBreakLines(text, maxWidth) {
+			layout.lines = append(layout.lines, textLine{
+				text:  string(row.Runes[row.StartIndex:row.EndIndex]),
+				width: row.Width,
+			})
+		}
+	} else {
+		for _, line := range strings.Split(text, "\n") {
+			width, _ := ctx.TextBounds(0, 0, line)
+			layout.lines = append(layout.lines, textLine{text: line, width: width})
+		}
+	}
+
+	if s.maxLines != unset && len(layout.lines) > s.maxLines {
+		layout.lines = layout.lines[:s.maxLines]
+	}
+
+	return layout
+}
+
+// collapseWhiteSpace removes the white space that should not be rendered, similar to the css white-space property
+func collapseWhiteSpace(text string, whiteSpace WhiteSpace) string {
+	if whiteSpace == WhiteSpacePre {
+		return strings.Replace(text, "\t", "    ", -1)
+	}
+
+	var b strings.Builder
+	pendingSpace := false
+	lineStart := true
+	for _, r := range text {
+		switch {
+		case r == '\n' && whiteSpace == WhiteSpaceWrap:
+			b.WriteRune('\n')
+			pendingSpace = false
+			lineStart = true
+		case unicode.IsSpace(r):
+			pendingSpace = true
+		default:
+			if pendingSpace && !lineStart {
+				b.WriteRune(' ')
+			}
+			pendingSpace = false
+			lineStart = false
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// textMeasureFunc returns a flex measure function that gives text its intrinsic size,
+// so that text contributes to the layout like in a browser
+func textMeasureFunc(ctx canvas, text string, s Styles) flex.MeasureFunc {
+	return func(node *flex.Node, width float32, widthMode flex.MeasureMode, height float32, heightMode flex.MeasureMode) flex.Size {
+		maxWidth := float32(math.Inf(1))
+		if widthMode != flex.MeasureModeUndefined {
+			maxWidth = width
+		}
+
+		textWidth, textHeight := layoutText(ctx, text, s, maxWidth).size()
+
+		return flex.Size{
+			// round up so that the text fits on the same lines when it is broken again during drawing
+			Width:  constrain(float32(math.Ceil(float64(textWidth))), width, widthMode),
+			Height: constrain(textHeight, height, heightMode),
+		}
+	}
+}
+
+// constrain applies the constraint given by flex to a measured size
+func constrain(measured, available float32, mode flex.MeasureMode) float32 {
+	switch mode {
+	case flex.MeasureModeExactly:
+		return available
+	case flex.MeasureModeAtMost:
+		if measured > available {
+			return available
+		}
+	}
+	return measured
+}
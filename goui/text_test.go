@@ -0,0 +1,107 @@
+package goui
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCollapseWhiteSpace(t *testing.T) {
+	tests := []struct {
+		text       string
+		whiteSpace WhiteSpace
+		want       string
+	}{
+		{"  hello   world  ", WhiteSpaceWrap, "hello world"},
+		{"hello\t\tworld", WhiteSpaceWrap, "hello world"},
+		{"one  \n   two", WhiteSpaceWrap, "one\ntwo"},
+		{"one\n\ntwo", WhiteSpaceWrap, "one\n\ntwo"},
+		{"one \n two", WhiteSpaceNoWrap, "one two"},
+		{"  keep  \n\tall", WhiteSpacePre, "  keep  \n    all"},
+	}
+	for _, test := range tests {
+		if got := collapseWhiteSpace(test.text, test.whiteSpace); got != test.want {
+			t.Errorf("collapseWhiteSpace(%q, %v) = %q, expected %q", test.text, test.whiteSpace, got, test.want)
+		}
+	}
+}
+
+func newTestCanvas(t *testing.T) *softCanvas {
+	t.Helper()
+	ctx := newSoftCanvas(100, 100)
+	if err := loadFonts(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return ctx
+}
+
+func lineTexts(layout textLayout) []string {
+	var lines []string
+	for _, line := range layout.lines {
+		lines = append(lines, strings.TrimSpace(line.text))
+	}
+	return lines
+}
+
+func TestLayoutTextWrapping(t *testing.T) {
+	ctx := newTestCanvas(t)
+	text := "the quick brown fox jumps over the lazy dog"
+	s := NewStyles().FontSize(20)
+
+	single := layoutText(ctx, text, s, float32(math.Inf(1)))
+	if len(single.lines) != 1 {
+		t.Fatalf("text without a width has %v lines", len(single.lines))
+	}
+
+	maxWidth := single.lines[0].width / 3
+	wrapped := layoutText(ctx, text, s, maxWidth)
+	if len(wrapped.lines) < 3 {
+		t.Fatalf("text wrapped at a third of its width has %v lines", len(wrapped.lines))
+	}
+	for _, line := range wrapped.lines {
+		if line.width > maxWidth {
+			t.Errorf("line %q is %v wide, more than %v", line.text, line.width, maxWidth)
+		}
+	}
+	if got := strings.Join(lineTexts(wrapped), " "); got != text {
+		t.Errorf("wrapped lines are %q", got)
+	}
+
+	_, height := wrapped.size()
+	if height != float32(len(wrapped.lines))*wrapped.lineHeight {
+		t.Errorf("height %v is not the height of %v lines", height, len(wrapped.lines))
+	}
+
+	noWrap := layoutText(ctx, text, s.WhiteSpace(WhiteSpaceNoWrap), maxWidth)
+	if len(noWrap.lines) != 1 {
+		t.Errorf("text that doesn't wrap has %v lines", len(noWrap.lines))
+	}
+}
+
+func TestLayoutTextMaxLines(t *testing.T) {
+	ctx := newTestCanvas(t)
+	text := "one\ntwo\nthree\nfour"
+
+	layout := layoutText(ctx, text, NewStyles().MaxLines(2), float32(math.Inf(1)))
+	if got := lineTexts(layout); len(got) != 2 || got[0] != "one" || got[1] != "two" {
+		t.Errorf("text truncated at 2 lines has lines %q", got)
+	}
+
+	layout = layoutText(ctx, text, NewStyles().MaxLines(10), float32(math.Inf(1)))
+	if len(layout.lines) != 4 {
+		t.Errorf("text with more max lines than lines has %v lines", len(layout.lines))
+	}
+}
+
+func TestLayoutTextLineHeight(t *testing.T) {
+	ctx := newTestCanvas(t)
+
+	normal := layoutText(ctx, "a\nb", NewStyles(), float32(math.Inf(1)))
+	double := layoutText(ctx, "a\nb", NewStyles().LineHeight(2), float32(math.Inf(1)))
+	if normal.lineHeight != normal.fontHeight*defaultLineHeight {
+		t.Errorf("default line height is %v for a font height of %v", normal.lineHeight, normal.fontHeight)
+	}
+	if double.lineHeight != double.fontHeight*2 {
+		t.Errorf("line height 2 is %v for a font height of %v", double.lineHeight, double.fontHeight)
+	}
+}
@@ -0,0 +1,250 @@
+package goui
+
+import (
+	"image/color"
+	"sync"
+)
+
+// Theme holds the design tokens of a UI: named colors, a spacing scale, corner radii and the fonts of text
+// roles. The widgets also draw with its colors, so switching between DarkTheme and LightTheme with
+// UI.SetTheme changes the look of everything that doesn't set its own colors.
+//
+// The colors that the widgets use are:
+//
+//	background        the window
+//	text.primary      text, and borders without a color
+//	text.secondary    buttons
+//	text.disabled     disabled buttons
+//	text.placeholder  the placeholder of empty inputs
+//	surface           buttons
+//	surface.hover     hovered buttons
+//	surface.active    pressed buttons
+//	surface.disabled  disabled buttons
+//	surface.subtle    inputs, and images while they are loading
+//	selection         selected text
+//	scrollbar         scrollbars
+//	focus             the focus ring
+//	error             images that could not be loaded
+//
+// Only colors are tokens. Spacing, radii and fonts are plain values that Space, Radius and Font return when
+// they are called, so render functions that use them with UI.Theme get the sizes of the new theme when
+// SetTheme renders the UI again.
+type Theme struct {
+	Colors  map[string]color.Color
+	Spacing []float64 // the sizes used for margins and paddings, from small to large
+	Radii   map[string]float64
+	Fonts   map[string]Font
+
+	warnLock sync.Mutex      // the themes are shared by all UIs, which can render at the same time
+	warned   map[string]bool // names that were not found, which are only logged once
+}
+
+// Font is the font of a role like "body" or "heading"
+type Font struct {
+	Family     string
+	Size       float64
+	LineHeight float64 // 0 keeps the line height
+}
+
+// DarkTheme is the theme of a UI until UI.SetTheme is called, LightTheme is its light counterpart.
+// Change a Copy of them to customize them.
+var (
+	DarkTheme = &Theme{
+		Colors: map[string]color.Color{
+			"background":       color.RGBA{},
+			"text.primary":     color.RGBA{R: 255, G: 255, B: 255, A: 255},
+			"text.secondary":   color.RGBA{R: 255, G: 255, B: 255, A: 220},
+			"text.disabled":    color.RGBA{R: 255, G: 255, B: 255, A: 80},
+			"text.placeholder": color.RGBA{R: 255, G: 255, B: 255, A: 90},
+			"surface":          color.RGBA{R: 255, G: 255, B: 255, A: 30},
+			"surface.hover":    color.RGBA{R: 255, G: 255, B: 255, A: 45},
+			"surface.active":   color.RGBA{R: 255, G: 255, B: 255, A: 15},
+			"surface.disabled": color.RGBA{R: 255, G: 255, B: 255, A: 10},
+			"surface.subtle":   color.RGBA{R: 255, G: 255, B: 255, A: 20},
+			"accent":           color.RGBA{R: 57, G: 181, B: 74, A: 255},
+			"selection":        color.RGBA{R: 57, G: 130, B: 200, A: 160},
+			"scrollbar":        color.RGBA{R: 255, G: 255, B: 255, A: 80},
+			"focus":            color.RGBA{R: 90, G: 160, B: 255, A: 255},
+			"error":            color.RGBA{R: 220, G: 60, B: 60, A: 200},
+		},
+		Spacing: defaultSpacing(),
+		Radii:   defaultRadii(),
+		Fonts:   defaultFonts(),
+	}
+
+	LightTheme = &Theme{
+		Colors: map[string]color.Color{
+			"background":       color.RGBA{R: 250, G: 250, B: 250, A: 255},
+			"text.primary":     color.RGBA{R: 0, G: 0, B: 0, A: 222},
+			"text.secondary":   color.RGBA{R: 0, G: 0, B: 0, A: 190},
+			"text.disabled":    color.RGBA{R: 0, G: 0, B: 0, A: 80},
+			"text.placeholder": color.RGBA{R: 0, G: 0, B: 0, A: 100},
+			"surface":          color.RGBA{R: 0, G: 0, B: 0, A: 20},
+			"surface.hover":    color.RGBA{R: 0, G: 0, B: 0, A: 32},
+			"surface.active":   color.RGBA{R: 0, G: 0, B: 0, A: 12},
+			"surface.disabled": color.RGBA{R: 0, G: 0, B: 0, A: 8},
+			"surface.subtle":   color.RGBA{R: 0, G: 0, B: 0, A: 14},
+			"accent":           color.RGBA{R: 46, G: 160, B: 62, A: 255},
+			"selection":        color.RGBA{R: 57, G: 130, B: 200, A: 90},
+			"scrollbar":        color.RGBA{R: 0, G: 0, B: 0, A: 70},
+			"focus":            color.RGBA{R: 40, G: 110, B: 230, A: 255},
+			"error":            color.RGBA{R: 200, G: 40, B: 40, A: 220},
+		},
+		Spacing: defaultSpacing(),
+		Radii:   defaultRadii(),
+		Fonts:   defaultFonts(),
+	}
+)
+
+func defaultSpacing() []float64 {
+	return []float64{0, 2, 4, 8, 12, 16, 24, 32, 48, 64}
+}
+
+func defaultRadii() map[string]float64 {
+	return map[string]float64{"small": 3, "medium": 5, "large": 8}
+}
+
+func defaultFonts() map[string]Font {
+	return map[string]Font{
+		"body":    {Family: FontRegular, Size: defaultFontSize},
+		"caption": {Family: FontRegular, Size: 14},
+		"heading": {Family: FontBold, Size: 32},
+		"mono":    {Family: FontMonoRegular, Size: defaultFontSize},
+	}
+}
+
+// Token is a color of the theme, referred to by its name. Styles keep the name instead of the color, so
+// widgets change their color when the theme of the UI is switched. Used as a color.Color on its own,
+// outside of the styles of a widget, it has the color of DarkTheme whatever the theme of the UI is, use
+// UI.Theme().Color to get the color of the current theme instead.
+type Token string
+
+func (t Token) RGBA() (r, g, b, a uint32) {
+	return DarkTheme.Color(string(t)).RGBA()
+}
+
+// Token returns a reference to the color with the given name, like "text.primary"
+func (t *Theme) Token(name string) Token {
+	if _, ok := t.Colors[name]; !ok {
+		t.warn("color", name)
+	}
+	return Token(name)
+}
+
+// Color returns the color with the given name, or transparent if the theme doesn't have it
+func (t *Theme) Color(name string) color.Color {
+	if c, ok := t.Colors[name]; ok {
+		return c
+	}
+	t.warn("color", name)
+	return color.RGBA{}
+}
+
+// Space returns the size of the step of the spacing scale, steps past the end of the scale are the largest size
+func (t *Theme) Space(step int) float64 {
+	if len(t.Spacing) == 0 || step < 0 {
+		return 0
+	}
+	if step >= len(t.Spacing) {
+		return t.Spacing[len(t.Spacing)-1]
+	}
+	return t.Spacing[step]
+}
+
+// Radius returns the corner radius with the given name, like "medium"
+func (t *Theme) Radius(name string) float64 {
+	if r, ok := t.Radii[name]; ok {
+		return r
+	}
+	t.warn("radius", name)
+	return 0
+}
+
+// Font returns the font of the role, like "body", see Styles.Font
+func (t *Theme) Font(role string) Font {
+	if f, ok := t.Fonts[role]; ok {
+		return f
+	}
+	t.warn("font", role)
+	return t.Fonts["body"]
+}
+
+// Copy returns a copy of the theme, which can be changed without changing the original
+func (t *Theme) Copy() *Theme {
+	c := &Theme{
+		Colors:  map[string]color.Color{},
+		Spacing: append([]float64{}, t.Spacing...),
+		Radii:   map[string]float64{},
+		Fonts:   map[string]Font{},
+	}
+	for name, col := range t.Colors {
+		c.Colors[name] = col
+	}
+	for name, r := range t.Radii {
+		c.Radii[name] = r
+	}
+	for role, f := range t.Fonts {
+		c.Fonts[role] = f
+	}
+	return c
+}
+
+func (t *Theme) warn(kind, name string) {
+	t.warnLock.Lock()
+	defer t.warnLock.Unlock()
+	if t.warned == nil {
+		t.warned = map[string]bool{}
+	}
+	if !t.warned[kind+":"+name] {
+		logWarning("theme has no", kind, name)
+		t.warned[kind+":"+name] = true
+	}
+}
+
+// color returns the color the widgets use for name, it uses DarkTheme for styles that were not applied to a widget
+func (t *Theme) color(name string) color.Color {
+	if t == nil {
+		t = DarkTheme
+	}
+	return t.Color(name)
+}
+
+// resolve returns the color of a token, other colors are returned unchanged
+func (t *Theme) resolve(c color.Color) color.Color {
+	if token, ok := c.(Token); ok {
+		return t.color(string(token))
+	}
+	return c
+}
+
+// themed returns the styles with the colors of the theme instead of tokens, and remembers the theme
+// for the colors that the widgets draw with
+func (h Styles) themed(theme *Theme) Styles {
+	h.theme = theme
+	h.color = theme.resolve(h.color)
+	h.background = theme.resolve(h.background)
+	h.borderColor.top = theme.resolve(h.borderColor.top)
+	h.borderColor.right = theme.resolve(h.borderColor.right)
+	h.borderColor.bottom = theme.resolve(h.borderColor.bottom)
+	h.borderColor.left = theme.resolve(h.borderColor.left)
+	h.focusRingColor = theme.resolve(h.focusRingColor)
+
+	if h.boxShadows != nil {
+		shadows := make([]boxShadow, len(h.boxShadows))
+		for i, shadow := range h.boxShadows {
+			shadow.color = theme.resolve(shadow.color)
+			shadows[i] = shadow
+		}
+		h.boxShadows = shadows
+	}
+	if h.backgroundPaint != nil && len(h.backgroundPaint.stops) > 0 {
+		paint := *h.backgroundPaint
+		paint.stops = make([]GradientStop, len(h.backgroundPaint.stops))
+		for i, stop := range h.backgroundPaint.stops {
+			stop.Color = theme.resolve(stop.Color)
+			paint.stops[i] = stop
+		}
+		h.backgroundPaint = &paint
+	}
+	return h
+}
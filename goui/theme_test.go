@@ -0,0 +1,92 @@
+package goui
+
+import (
+	"image/color"
+	"sync"
+	"testing"
+)
+
+// checkColor checks the color of a pixel of the last frame, allowing for rounding
+func checkColor(t *testing.T, d *Driver, x, y int, want color.Color) {
+	t.Helper()
+	got := d.Image().RGBAAt(x, y)
+	r, g, b, a := want.RGBA()
+	diff := func(c uint8, w uint32) bool { return int(c)-int(w>>8) > 1 || int(w>>8)-int(c) > 1 }
+	if diff(got.R, r) || diff(got.G, g) || diff(got.B, b) || diff(got.A, a) {
+		t.Errorf("pixel %v, %v is %v, expected %v", x, y, got, want)
+	}
+}
+
+func TestDriverSetTheme(t *testing.T) {
+	var ui UI
+	d := newTestDriver(t, 200, 200, func(g UI) {
+		ui = g
+		g.Box(func() {}).Key("accent").Styles(NewStyles().Width(50).Height(50).Background(Token("accent")))
+		g.Box(func() {}).Key("fixed").Styles(NewStyles().Width(50).Height(50).Background(color.RGBA{R: 255, A: 255}))
+	})
+
+	checkColor(t, d, 25, 25, DarkTheme.Colors["accent"])
+	checkColor(t, d, 25, 75, color.RGBA{R: 255, A: 255})
+	checkColor(t, d, 150, 150, DarkTheme.Colors["background"])
+
+	ui.SetTheme(LightTheme)
+	d.Step()
+	checkColor(t, d, 25, 25, LightTheme.Colors["accent"])
+	checkColor(t, d, 25, 75, color.RGBA{R: 255, A: 255})
+	checkColor(t, d, 150, 150, LightTheme.Colors["background"])
+	if s := find(t, d, "accent").Styles; s.background != LightTheme.Colors["accent"] {
+		t.Errorf("the token in the styles has the color %v after switching the theme", s.background)
+	}
+}
+
+func TestDriverSetThemeButton(t *testing.T) {
+	var ui UI
+	d := newTestDriver(t, 200, 200, func(g UI) {
+		ui = g
+		g.Button("").Key("button").Styles(NewStyles().Width(100).Height(40))
+	})
+	// away from the button, which has other colors while it is hovered
+	d.MoveMouse(150, 150)
+	for _, theme := range []*Theme{LightTheme, DarkTheme} {
+		ui.SetTheme(theme)
+		d.Step()
+		s := find(t, d, "button").Styles
+		if s.color != theme.Colors["text.secondary"] || s.background != theme.Colors["surface"] {
+			t.Errorf("the button has color %v and background %v, expected the colors of the theme", s.color, s.background)
+		}
+		// the surface of the button is drawn over the background of the window
+		bg := theme.Colors["background"].(color.RGBA)
+		surface := theme.Colors["surface"].(color.RGBA)
+		blend := func(b, s uint8) uint8 {
+			return uint8((int(s)*int(surface.A) + int(b)*(255-int(surface.A))) / 255)
+		}
+		checkColor(t, d, 50, 20, color.RGBA{
+			R: blend(bg.R, surface.R), G: blend(bg.G, surface.G), B: blend(bg.B, surface.B),
+			A: surface.A + uint8(int(bg.A)*(255-int(surface.A))/255),
+		})
+	}
+}
+
+func TestTokenOutsideOfStyles(t *testing.T) {
+	r, g, b, a := Token("accent").RGBA()
+	if (color.RGBA64{uint16(r), uint16(g), uint16(b), uint16(a)}) != color.RGBA64Model.Convert(DarkTheme.Colors["accent"]) {
+		t.Errorf("a token on its own doesn't have the color of DarkTheme")
+	}
+}
+
+func TestThemeWarnConcurrently(t *testing.T) {
+	theme := DarkTheme.Copy()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			theme.Color("missing")
+			theme.Radius("missing")
+		}()
+	}
+	wg.Wait()
+	if len(theme.warned) != 2 {
+		t.Errorf("%v warnings were remembered, expected 2", len(theme.warned))
+	}
+}
@@ -0,0 +1,145 @@
+package goui
+
+import (
+	"math"
+)
+
+// transform is a 2D affine transformation laid out like nanovgo.TransformMatrix,
+// it moves x, y to a*x + c*y + e, b*x + d*y + f
+type transform [6]float32
+
+var identityTransform = transform{1, 0, 0, 1, 0, 0}
+
+func translation(x, y float32) transform {
+	return transform{1, 0, 0, 1, x, y}
+}
+
+func scaling(x, y float32) transform {
+	return transform{x, 0, 0, y, 0, 0}
+}
+
+// rotation rotates clockwise by the angle in radians, since y goes down
+func rotation(angle float32) transform {
+	sin, cos := math.Sincos(float64(angle))
+	return transform{float32(cos), float32(sin), float32(-sin), float32(cos), 0, 0}
+}
+
+// multiply returns the transformation that applies o first and then t,
+// like calling the transform functions of nanovgo with o after t
+func (t transform) multiply(o transform) transform {
+	return transform{
+		t[0]*o[0] + t[2]*o[1],
+		t[1]*o[0] + t[3]*o[1],
+		t[0]*o[2] + t[2]*o[3],
+		t[1]*o[2] + t[3]*o[3],
+		t[0]*o[4] + t[2]*o[5] + t[4],
+		t[1]*o[4] + t[3]*o[5] + t[5],
+	}
+}
+
+// inverse returns the transformation that undoes t, or the identity if t can't be undone because it scales to 0
+func (t transform) inverse() transform {
+	det := t[0]*t[3] - t[1]*t[2]
+	if det > -1e-6 && det < 1e-6 {
+		return identityTransform
+	}
+	return transform{
+		t[3] / det,
+		-t[1] / det,
+		-t[2] / det,
+		t[0] / det,
+		(t[2]*t[5] - t[3]*t[4]) / det,
+		(t[1]*t[4] - t[0]*t[5]) / det,
+	}
+}
+
+func (t transform) apply(x, y float32) (float32, float32) {
+	return t[0]*x + t[2]*y + t[4], t[1]*x + t[3]*y + t[5]
+}
+
+// averageScale is used to scale stroke widths, like in nanovgo
+func (t transform) averageScale() float32 {
+	sx := math.Hypot(float64(t[0]), float64(t[1]))
+	sy := math.Hypot(float64(t[2]), float64(t[3]))
+	return float32(sx+sy) / 2
+}
+
+type transformParts int
+
+const (
+	transformTranslate transformParts = 1 << iota
+	transformScale
+	transformRotate
+	transformOrigin
+)
+
+// transformStyles keeps track of which parts were set, since any value is valid for them
+type transformStyles struct {
+	translateX, translateY float64
+	scaleX, scaleY         float64
+	rotate                 float64 // degrees
+	originX, originY       float64 // percent of the size
+	set                    transformParts
+}
+
+func (t transformStyles) combine(o transformStyles) transformStyles {
+	if o.set&transformTranslate != 0 {
+		t.translateX, t.translateY = o.translateX, o.translateY
+	}
+	if o.set&transformScale != 0 {
+		t.scaleX, t.scaleY = o.scaleX, o.scaleY
+	}
+	if o.set&transformRotate != 0 {
+		t.rotate = o.rotate
+	}
+	if o.set&transformOrigin != 0 {
+		t.originX, t.originY = o.originX, o.originY
+	}
+	t.set |= o.set
+	return t
+}
+
+// transformed returns if the widget is drawn differently than it is laid out, and needs its own canvas state
+func (w *widgetContainer) transformed() bool {
+	return w.handle.styles.opacity != unset || w.handle.styles.transform.set&^transformOrigin != 0
+}
+
+// transformSteps returns the parts of the transformation of the widget, in the order they are applied
+func (w *widgetContainer) transformSteps() (originX, originY, translateX, translateY, angle, scaleX, scaleY float32) {
+	t := w.handle.styles.transform
+	originX, originY = w.x+w.width/2, w.y+w.height/2
+	if t.set&transformOrigin != 0 {
+		originX = w.x + w.width*float32(t.originX)/100
+		originY = w.y + w.height*float32(t.originY)/100
+	}
+	scaleX, scaleY = 1, 1
+	if t.set&transformScale != 0 {
+		scaleX, scaleY = float32(t.scaleX), float32(t.scaleY)
+	}
+	return originX, originY, float32(t.translateX), float32(t.translateY), float32(t.rotate * math.Pi / 180), scaleX, scaleY
+}
+
+// localTransform returns the transformation of the widget itself, without the ones of its ancestors
+func (w *widgetContainer) localTransform() transform {
+	if w.handle.styles.transform.set&^transformOrigin == 0 {
+		return identityTransform
+	}
+	originX, originY, translateX, translateY, angle, scaleX, scaleY := w.transformSteps()
+	return translation(originX+translateX, originY+translateY).
+		multiply(rotation(angle)).
+		multiply(scaling(scaleX, scaleY)).
+		multiply(translation(-originX, -originY))
+}
+
+// applyTransform sets the opacity and transformation of the widget on the canvas, the state has to be saved before
+func applyTransform(ctx canvas, w *widgetContainer) {
+	ctx.SetGlobalAlpha(w.alpha)
+	if w.handle.styles.transform.set&^transformOrigin == 0 {
+		return
+	}
+	originX, originY, translateX, translateY, angle, scaleX, scaleY := w.transformSteps()
+	ctx.Translate(originX+translateX, originY+translateY)
+	ctx.Rotate(angle)
+	ctx.Scale(scaleX, scaleY)
+	ctx.Translate(-originX, -originY)
+}
@@ -0,0 +1,60 @@
+package goui
+
+import (
+	"math"
+	"testing"
+)
+
+func closeTo(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-4
+}
+
+func TestTransformApply(t *testing.T) {
+	tests := []struct {
+		name         string
+		transform    transform
+		x, y         float32
+		wantX, wantY float32
+	}{
+		{"identity", identityTransform, 3, 4, 3, 4},
+		{"translation", translation(10, -5), 3, 4, 13, -1},
+		{"scaling", scaling(2, 3), 3, 4, 6, 12},
+		{"rotation is clockwise", rotation(math.Pi / 2), 1, 0, 0, 1},
+		{"scaling before translation", translation(10, 0).multiply(scaling(2, 2)), 1, 1, 12, 2},
+		{"translation before scaling", scaling(2, 2).multiply(translation(10, 0)), 1, 1, 22, 2},
+		{"around an origin", translation(5, 5).multiply(rotation(math.Pi)).multiply(translation(-5, -5)), 6, 5, 4, 5},
+	}
+	for _, test := range tests {
+		x, y := test.transform.apply(test.x, test.y)
+		if !closeTo(x, test.wantX) || !closeTo(y, test.wantY) {
+			t.Errorf("%v: %v, %v moved to %v, %v, expected %v, %v", test.name, test.x, test.y, x, y, test.wantX, test.wantY)
+		}
+	}
+}
+
+func TestTransformInverse(t *testing.T) {
+	transforms := []transform{
+		identityTransform,
+		translation(10, 20),
+		scaling(2, 0.5),
+		rotation(0.7),
+		translation(10, 20).multiply(rotation(0.7)).multiply(scaling(2, 3)).multiply(translation(-5, -5)),
+	}
+	for _, transform := range transforms {
+		for _, point := range [][2]float32{{0, 0}, {3, 4}, {-20, 7.5}} {
+			x, y := transform.apply(point[0], point[1])
+			x, y = transform.inverse().apply(x, y)
+			if !closeTo(x, point[0]) || !closeTo(y, point[1]) {
+				t.Errorf("%v: %v came back as %v, %v", transform, point, x, y)
+			}
+		}
+		if product := transform.multiply(transform.inverse()); !closeTo(product[0], 1) || !closeTo(product[1], 0) ||
+			!closeTo(product[2], 0) || !closeTo(product[3], 1) || !closeTo(product[4], 0) || !closeTo(product[5], 0) {
+			t.Errorf("%v times its inverse is %v", transform, product)
+		}
+	}
+
+	if inverse := scaling(0, 1).inverse(); inverse != identityTransform {
+		t.Errorf("the inverse of a transformation that can't be undone is %v", inverse)
+	}
+}
@@ -1,30 +1,61 @@
 package goui
 
 import (
-	"fmt"
-
 	"github.com/kjk/flex"
-	"github.com/shibukawa/nanovgo"
 )
 
 type widgetContainer struct {
 	widget widget
 	handle *Handle
 	layout *flex.Node
+	parent *widgetContainer
+
+	// identity of the widget between renders, see assignIDs
+	id       string
+	callSite string
+	stateID  string // id from the keys known when the widget is created, see State
+
+	// absolute bounds, updated after every layout pass
+	x, y, width, height float32
+
+	// opacity and transformation from the layout to the window, including the ones of the ancestors
+	alpha     float32
+	transform transform
+	inverse   transform
+
+	state     widgetState
+	focusRing bool
 }
 
 type widget interface {
-	render(ctx *nanovgo.Context, parentX, parentY float32, layout *flex.Node, style Styles)
+	render(ctx canvas, parentX, parentY float32, layout *flex.Node, style Styles)
+}
+
+// styledWidget is implemented by widgets with a default look, the styles given by the user are combined on top
+type styledWidget interface {
+	defaultStyles() Styles
 }
 
 type boxWidget struct {
-	children []*widgetContainer
+	children  []*widgetContainer
+	scroll    *scrollState
+	callSites map[string]int // how many children were created at each call site, see stateID
 }
 
 func (g *gui) Box(children func()) *Handle {
+	return g.box("", children)
+}
+
+// KeyedBox creates a box with a key like Handle.Key, which is known before the children are created.
+// This keeps the values of State in the box when boxes are added, removed or reordered.
+func (g *gui) KeyedBox(key string, children func()) *Handle {
+	return g.box(key, children)
+}
+
+func (g *gui) box(key string, children func()) *Handle {
 	// create new box and add it to the current container
 	newBox := &boxWidget{}
-	newBoxContainer, handle := g.addWidgetExtra(newBox)
+	newBoxContainer, handle := g.addWidgetExtra(newBox, key)
 
 	// assume the position of the current container, and add children to that container
 	old := g.currentBox
@@ -35,14 +66,53 @@ func (g *gui) Box(children func()) *Handle {
 	return handle
 }
 
-func (w *boxWidget) render(ctx *nanovgo.Context, parentX, parentY float32, l *flex.Node, s Styles) {
+func (w *boxWidget) render(ctx canvas, parentX, parentY float32, l *flex.Node, s Styles) {
 	drawRect(ctx, parentX, parentY, l, s)
 
 	x := parentX + l.LayoutGetLeft()
 	y := parentY + l.LayoutGetTop()
 
+	clip := clipsChildren(s)
+	if clip {
+		ctx.Save()
+		ctx.IntersectScissor(paddingBox(parentX, parentY, l))
+	}
+
+	childX, childY := x, y
+	if w.scroll != nil {
+		childX -= w.scroll.x
+		childY -= w.scroll.y
+	}
 	for _, child := range w.children {
-		child.widget.render(ctx, x, y, child.layout, child.handle.styles)
+		transformed := child.transformed()
+		if transformed {
+			ctx.Save()
+			applyTransform(ctx, child)
+		}
+		child.widget.render(ctx, childX, childY, child.layout, child.handle.styles)
+		if child.state&stateFocus != 0 && child.focusRing {
+			drawFocusRing(ctx, childX, childY, child.layout, child.handle.styles)
+		}
+		if transformed {
+			ctx.Restore()
+		}
+	}
+
+	if w.scroll != nil {
+		drawScrollbars(ctx, x, y, w.scroll, s.theme.color("scrollbar"))
+	}
+	if clip {
+		ctx.Restore()
+	}
+}
+
+// walk calls fn for the widget and all of its descendants, in document order
+func (w *widgetContainer) walk(fn func(w *widgetContainer)) {
+	fn(w)
+	if box, ok := w.widget.(*boxWidget); ok {
+		for _, child := range box.children {
+			child.walk(fn)
+		}
 	}
 }
 
@@ -54,20 +124,56 @@ type textWidget struct {
 	text string
 }
 
-func (w *textWidget) render(ctx *nanovgo.Context, parentX, parentY float32, l *flex.Node, s Styles) {
+func (w *textWidget) render(ctx canvas, parentX, parentY float32, l *flex.Node, s Styles) {
 	//drawRect(ctx, parentX, parentY, l, s)
 	drawText(ctx, w.text, parentX, parentY, l, s)
 }
 
-type InputState struct {
-	Text      string
-	Focused   bool
-	CursorPos int
-	OnChange  func(text string)
+func (g *gui) Image(path string) *Handle {
+	return g.addWidget(&imageWidget{path: path, res: g.resources.image(g.ctx, path)})
+}
 
-	Managed bool
+type imageWidget struct {
+	path string
+	res  resource
 }
 
-func (i InputState) String() string {
-	return fmt.Sprintf("InputState: (%v)", i.Text)
+func (w *imageWidget) render(ctx canvas, parentX, parentY float32, l *flex.Node, s Styles) {
+	drawRect(ctx, parentX, parentY, l, s)
+
+	switch w.res.state {
+	case resourceLoading:
+		drawPlaceholder(ctx, parentX, parentY, l, s, false)
+	case resourceFailed:
+		drawPlaceholder(ctx, parentX, parentY, l, s, true)
+	case resourceLoaded:
+		drawImage(ctx, w.res, parentX, parentY, l, s)
+	}
+}
+
+// imageMeasureFunc gives images their intrinsic size, keeping the aspect ratio when only one side is constrained
+func imageMeasureFunc(imgWidth, imgHeight float32) flex.MeasureFunc {
+	return func(node *flex.Node, width float32, widthMode flex.MeasureMode, height float32, heightMode flex.MeasureMode) flex.Size {
+		if imgWidth == 0 || imgHeight == 0 {
+			return flex.Size{Width: constrain(0, width, widthMode), Height: constrain(0, height, heightMode)}
+		}
+
+		ratio := imgWidth / imgHeight
+		w, h := imgWidth, imgHeight
+
+		if widthMode == flex.MeasureModeExactly && heightMode != flex.MeasureModeExactly {
+			w, h = width, width/ratio
+		} else if heightMode == flex.MeasureModeExactly && widthMode != flex.MeasureModeExactly {
+			w, h = height*ratio, height
+		}
+
+		if widthMode == flex.MeasureModeAtMost && w > width {
+			w, h = width, width/ratio
+		}
+		if heightMode == flex.MeasureModeAtMost && h > height {
+			w, h = height*ratio, height
+		}
+
+		return flex.Size{Width: constrain(w, width, widthMode), Height: constrain(h, height, heightMode)}
+	}
 }
@@ -1,38 +1,80 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"image/color"
 	"os"
 	"strconv"
 
 	"./goui"
+	"./goui/css"
 )
 
 func main() {
+	debug := flag.Bool("debug", false, "reload the stylesheet, fonts and images when their files change")
+	flag.Parse()
+
 	items := []string{"Board /b/ - Random", "Board /g/ - Technology", "Board /pol/ - Politically incorrect"}
 	index := 2
+	search := &goui.InputState{Placeholder: "Search boards"}
 
-	err := goui.Render(func(g goui.UI) {
+	goui.Debug(*debug)
+	sheet, err := css.Load("main.css")
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	err = goui.Render(func(g goui.UI) {
+		if sheet != nil {
+			g.Stylesheet(sheet)
+		}
 		g.OnClick(func(ev goui.ClickEvent) {
 			fmt.Println(ev)
 		})
 		// and maybe mouse move
 
 		g.Text("Jux").
-			Styles(titleStyles).
+			Class("title").
 			Click(func(ev goui.ClickEvent) {
 				fmt.Println("button clicked", ev)
 			})
 
+		g.Image("cat.jpg").Styles(catStyles)
+		g.Input(search).Styles(searchStyles)
+
 		g.Box(func() {
 			for i, item := range items {
+				i := i
 				selected := i == index
 				g.Box(func() {
-					g.Text(item + " " + strconv.FormatBool(selected)).Styles(menuItemText)
-				}).Styles(menuItem, menuItemSelected(selected))
+					g.Text(item + " " + strconv.FormatBool(selected)).Class("menu-item-text")
+				}).
+					Key(item).
+					Class("menu-item").
+					Styles(menuItemSelected(selected)).
+					Click(func(ev goui.ClickEvent) {
+						index = i
+						ev.StopPropagation()
+					})
 			}
 		}).Styles(menuContainer)
+
+		g.Box(func() {
+			g.Button("Previous").
+				Disabled(index == 0).
+				Click(func(ev goui.ClickEvent) { index-- })
+			g.Button("Next").
+				Disabled(index == len(items)-1).
+				Click(func(ev goui.ClickEvent) { index++ })
+			g.Button("Toggle theme").
+				Click(func(ev goui.ClickEvent) {
+					if g.Theme() == goui.DarkTheme {
+						g.SetTheme(goui.LightTheme)
+					} else {
+						g.SetTheme(goui.DarkTheme)
+					}
+				})
+		}).Styles(buttonRow)
 	})
 	if err != nil {
 		fmt.Println(err)
@@ -40,38 +82,34 @@ func main() {
 	}
 }
 
-// or parse this stuff from css files?
-
 var (
-	textColor     = color.RGBA{R: 255, G: 255, B: 255, A: 153}
-	selectedColor = color.RGBA{R: 57, G: 181, B: 74, A: 255}
+	selectedColor = goui.Token("accent")
 )
 
 var (
-	titleStyles = goui.NewStyles().
-			Color(textColor).
-			FontSize(200).
-			TextAlign(goui.TextCenter).
-			FontFamily(goui.FontBold).
-			Margin(goui.EdgeAll, 40).
-			Padding(goui.EdgeAll, 20)
+	catStyles = goui.NewStyles().
+			Height(150).
+			Margin(goui.EdgeBottom, 20).
+			ObjectFit(goui.ObjectFitContain)
+
+	searchStyles = goui.NewStyles().
+			Width(400).
+			Margin(goui.EdgeBottom, 10).
+			AlignSelf(goui.AlignCenter)
 
 	menuContainer = goui.NewStyles().
 			MinWidth(400).
 			Height(600).
-			AlignSelf(goui.AlignCenter)
+			AlignSelf(goui.AlignCenter).
+			Overflow(goui.OverflowScroll)
 
-	menuItem = goui.NewStyles().
-			BorderRadius(5)
+	buttonRow = goui.NewStyles().
+			FlexDirection(goui.Row).
+			JustifyContent(goui.JustifyCenter)
 
 	menuItemSelected = goui.ConditionalStyles(
-		goui.NewStyles().Background(selectedColor),
+		goui.NewStyles().
+			Background(selectedColor).
+			Hover(goui.NewStyles().Background(selectedColor)),
 		goui.NewStyles())
-
-	menuItemText = goui.NewStyles().
-			FontSize(20).
-			FontFamily(goui.FontMonoRegular).
-			Color(textColor).
-			Margin(goui.EdgeVertical, 6).
-			Margin(goui.EdgeHorizontal, 15)
 )